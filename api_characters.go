@@ -51,7 +51,7 @@ func SearchCharactersByName(limit, offset string, requestBody string, client *ht
 	params.Add("offset", fmt.Sprintf("%s", offset))
 
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-	jsonData, err := getJsonDataFromURL("POST", apiURL, requestBody, client)
+	jsonData, err := getJsonDataFromURL("SearchCharactersByName", "POST", apiURL, requestBody, client)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +79,7 @@ func SearchCharactersById(chrID string, client *http.Client) ([]byte, error) {
 	baseURL := "https://api.bgm.tv/v0/characters/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, chrID)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchCharactersById", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +106,7 @@ SetCollectCharactersById
 func SetCollectCharactersById(chrID string, client *http.Client) (bool, error) {
 
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/characters/%s/collect", chrID)
-	err := getBoolDataFromURL("POST", apiURL, "", client)
+	err := getBoolDataFromURL("SetCollectCharactersById", "POST", apiURL, "", client)
 	if err != nil {
 		return false, err
 	}
@@ -134,7 +134,7 @@ func DeleteCollectCharactersById(chrID string, client *http.Client) (bool, error
 
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/characters/%s/collect", chrID)
 
-	err := getBoolDataFromURL("DELETE", apiURL, "", client)
+	err := getBoolDataFromURL("DeleteCollectCharactersById", "DELETE", apiURL, "", client)
 	if err != nil {
 		return false, err
 	}
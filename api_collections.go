@@ -9,10 +9,12 @@
 package lite_bangumi_api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 )
 
 /*
@@ -80,7 +82,7 @@ func SearchCollectionsByUserName(userName, subjectTypeName, typeName, limit, off
 	params.Add("offset", fmt.Sprintf("%s", offset))
 
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/%s/collections?%s", userName, params.Encode())
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchCollectionsByUserName", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +110,7 @@ SearchCollectionsByID
 */
 func SearchCollectionsByID(userName, subID string, client *http.Client) ([]byte, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/%s/collections/%s", userName, subID)
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchCollectionsByID", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +153,7 @@ AddOrEditCollectionsSubjectsInUsersByID
 func AddOrEditCollectionsSubjectsInUsersByID(subID, requestBody string, client *http.Client) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/-/collections/%s", subID)
 
-	err := getBoolDataFromURL("POST", apiURL, requestBody, client)
+	err := getBoolDataFromURL("AddOrEditCollectionsSubjectsInUsersByID", "POST", apiURL, requestBody, client)
 	if err != nil {
 		return false, err
 	}
@@ -193,7 +195,7 @@ EditCollectionsSubjectsInUsersByID
 */
 func EditCollectionsSubjectsInUsersByID(subID, requestBody string, client *http.Client) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/-/collections/%s", subID)
-	err := getBoolDataFromURL("PATCH", apiURL, requestBody, client)
+	err := getBoolDataFromURL("EditCollectionsSubjectsInUsersByID", "PATCH", apiURL, requestBody, client)
 	if err != nil {
 		return false, err
 	}
@@ -248,8 +250,8 @@ func SearchUsersCollectionsEpisodesBySubjectsID(subID, offset, limit, episodesTy
 	params.Add("limit", fmt.Sprintf("%s", limit))
 	params.Add("offset", fmt.Sprintf("%s", offset))
 
-	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/-/collections/%s/episodes?", subID, params.Encode())
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/-/collections/%s/episodes?%s", subID, params.Encode())
+	jsonData, err := getJsonDataFromURL("SearchUsersCollectionsEpisodesBySubjectsID", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -284,8 +286,8 @@ GetCollectionsSubjectsEpisodesInfo
   - @retval 如果bool为true，err为nil。如果bool为false，err表示错误信息
 */
 func GetCollectionsSubjectsEpisodesInfo(subID, requestBody string, client *http.Client) (bool, error) {
-	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/-/collections/%s", subID)
-	err := getBoolDataFromURL("PATCH", apiURL, requestBody, client)
+	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/-/collections/%s/episodes", subID)
+	err := getBoolDataFromURL("GetCollectionsSubjectsEpisodesInfo", "PATCH", apiURL, requestBody, client)
 	if err != nil {
 		return false, err
 	}
@@ -311,7 +313,7 @@ SearchCollectionsEpisodesInfo
 */
 func SearchCollectionsEpisodesInfo(epiID string, client *http.Client) ([]byte, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/-/collections/-/episodes/%s", epiID)
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchCollectionsEpisodesInfo", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +343,7 @@ UpdateCollectionEpisodesInfo
 */
 func UpdateCollectionEpisodesInfo(epiID, requestBody string, client *http.Client) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/-/collections/-/episodes/%s", epiID)
-	err := getBoolDataFromURL("PUT", apiURL, requestBody, client)
+	err := getBoolDataFromURL("UpdateCollectionEpisodesInfo", "PUT", apiURL, requestBody, client)
 	if err != nil {
 		return false, err
 	}
@@ -367,7 +369,7 @@ SearchCharactersCollectionsByUserName
 */
 func SearchCharactersCollectionsByUserName(userName string, client *http.Client) ([]byte, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/%s/collections/-/characters", userName)
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchCharactersCollectionsByUserName", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -393,7 +395,7 @@ SearchCharactersCollectionsByUserNameAndID
 */
 func SearchCharactersCollectionsByUserNameAndID(userName, chrID string, client *http.Client) ([]byte, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/%s/collections/-/characters/%s", userName, chrID)
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchCharactersCollectionsByUserNameAndID", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -419,7 +421,7 @@ SearchPersonsCollectionsByUserName
 */
 func SearchPersonsCollectionsByUserName(userName string, client *http.Client) ([]byte, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/%s/collections/-/persons", userName)
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchPersonsCollectionsByUserName", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -445,9 +447,189 @@ SearchPersonsCollectionsByUserNameAndID
 */
 func SearchPersonsCollectionsByUserNameAndID(userName, perID string, client *http.Client) ([]byte, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/%s/collections/-/persons/%s", userName, perID)
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchPersonsCollectionsByUserNameAndID", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
 	return jsonData, nil
 }
+
+/*
+GetAllCollectionsByUserName
+
+  - @brief 获取用户某个条目类型、某个收藏类型下的全部收藏，会自动翻页。
+
+    API：/v0/users/{username}/collections
+
+  - @param
+
+    【userName】：用户名。
+
+    【subjectTypeName】：条目类型（只能是以下字符串：书籍、动漫、音乐、游戏、三次元。如果不满足以上字符串，则会返回错误）
+
+    【typeName】：收藏类型（只能是以下字符串：想看、看过、在看、搁置、抛弃。如果不满足以上字符串，则将全局搜索）
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]UserSubjectCollection和一个err。
+
+  - @retval []UserSubjectCollection是全部收藏，err表示错误。如果err为nil，则没有错误。
+*/
+func GetAllCollectionsByUserName(userName, subjectTypeName, typeName string, client *http.Client) ([]UserSubjectCollection, error) {
+	const pageSize = 50
+	var collections []UserSubjectCollection
+	for offset := 0; ; {
+		jsonData, err := SearchCollectionsByUserName(userName, subjectTypeName, typeName, strconv.Itoa(pageSize), strconv.Itoa(offset), client)
+		if err != nil {
+			return nil, err
+		}
+		var page PagedUserCollection
+		if err = json.Unmarshal(jsonData, &page); err != nil {
+			errMsg := errors.New("GetAllCollectionsByUserName：解析返回体失败")
+			return nil, errMsg
+		}
+		collections = append(collections, page.Data...)
+		offset += len(page.Data)
+		if offset >= page.Total {
+			break
+		}
+		if len(page.Data) == 0 {
+			errMsg := errors.New("GetAllCollectionsByUserName：返回的收藏数少于total")
+			return nil, errMsg
+		}
+	}
+	return collections, nil
+}
+
+/*
+GetAllUserEpisodeCollections
+
+  - @brief 获取当前用户在某个条目中某个章节类型的全部章节收藏，会自动翻页。需要 access token。
+
+    API：/v0/users/-/collections/{subject_id}/episodes
+
+  - @param
+
+    【subID】：条目ID。
+
+    【episodesType】：章节类型（只能是以下字符串：本篇、特别篇、OP、ED、预告/宣传/广告、MAD、其他。如果不满足以上字符串，则将全局搜索）
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]UserEpisodeCollection和一个err。
+
+  - @retval []UserEpisodeCollection是全部章节收藏，err表示错误。如果err为nil，则没有错误。
+*/
+func GetAllUserEpisodeCollections(subID, episodesType string, client *http.Client) ([]UserEpisodeCollection, error) {
+	const pageSize = 100
+	var collections []UserEpisodeCollection
+	for offset := 0; ; {
+		jsonData, err := SearchUsersCollectionsEpisodesBySubjectsID(subID, strconv.Itoa(offset), strconv.Itoa(pageSize), episodesType, client)
+		if err != nil {
+			return nil, err
+		}
+		var page PagedUserEpisodeCollection
+		if err = json.Unmarshal(jsonData, &page); err != nil {
+			errMsg := errors.New("GetAllUserEpisodeCollections：解析返回体失败")
+			return nil, errMsg
+		}
+		collections = append(collections, page.Data...)
+		offset += len(page.Data)
+		if offset >= page.Total {
+			break
+		}
+		if len(page.Data) == 0 {
+			errMsg := errors.New("GetAllUserEpisodeCollections：返回的章节收藏数少于total")
+			return nil, errMsg
+		}
+	}
+	return collections, nil
+}
+
+/*
+GetAllCharacterCollectionsByUserName
+
+  - @brief 获取用户的全部角色收藏，会自动翻页。
+
+    API：/v0/users/{username}/collections/-/characters
+
+  - @param
+
+    【userName】：用户名。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]UserCharacterCollection和一个err。
+
+  - @retval []UserCharacterCollection是全部角色收藏，err表示错误。如果err为nil，则没有错误。
+*/
+func GetAllCharacterCollectionsByUserName(userName string, client *http.Client) ([]UserCharacterCollection, error) {
+	const pageSize = 50
+	var collections []UserCharacterCollection
+	for offset := 0; ; {
+		apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/%s/collections/-/characters?limit=%d&offset=%d", userName, pageSize, offset)
+		jsonData, err := getJsonDataFromURL("GetAllCharacterCollectionsByUserName", "GET", apiURL, "", client)
+		if err != nil {
+			return nil, err
+		}
+		var page PagedUserCharacterCollection
+		if err = json.Unmarshal(jsonData, &page); err != nil {
+			errMsg := errors.New("GetAllCharacterCollectionsByUserName：解析返回体失败")
+			return nil, errMsg
+		}
+		collections = append(collections, page.Data...)
+		offset += len(page.Data)
+		if offset >= page.Total {
+			break
+		}
+		if len(page.Data) == 0 {
+			errMsg := errors.New("GetAllCharacterCollectionsByUserName：返回的收藏数少于total")
+			return nil, errMsg
+		}
+	}
+	return collections, nil
+}
+
+/*
+GetAllPersonCollectionsByUserName
+
+  - @brief 获取用户的全部人物收藏，会自动翻页。
+
+    API：/v0/users/{username}/collections/-/persons
+
+  - @param
+
+    【userName】：用户名。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]UserPersonCollection和一个err。
+
+  - @retval []UserPersonCollection是全部人物收藏，err表示错误。如果err为nil，则没有错误。
+*/
+func GetAllPersonCollectionsByUserName(userName string, client *http.Client) ([]UserPersonCollection, error) {
+	const pageSize = 50
+	var collections []UserPersonCollection
+	for offset := 0; ; {
+		apiURL := fmt.Sprintf("https://api.bgm.tv/v0/users/%s/collections/-/persons?limit=%d&offset=%d", userName, pageSize, offset)
+		jsonData, err := getJsonDataFromURL("GetAllPersonCollectionsByUserName", "GET", apiURL, "", client)
+		if err != nil {
+			return nil, err
+		}
+		var page PagedUserPersonCollection
+		if err = json.Unmarshal(jsonData, &page); err != nil {
+			errMsg := errors.New("GetAllPersonCollectionsByUserName：解析返回体失败")
+			return nil, errMsg
+		}
+		collections = append(collections, page.Data...)
+		offset += len(page.Data)
+		if offset >= page.Total {
+			break
+		}
+		if len(page.Data) == 0 {
+			errMsg := errors.New("GetAllPersonCollectionsByUserName：返回的收藏数少于total")
+			return nil, errMsg
+		}
+	}
+	return collections, nil
+}
@@ -9,9 +9,12 @@
 package lite_bangumi_api
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 )
 
 /*
@@ -67,7 +70,7 @@ func SearchEpisodesByEpisodesName(sbjID, typeName, limit, offset string, client
 	params.Add("offset", fmt.Sprintf("%s", offset))
 
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchEpisodesByEpisodesName", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -95,9 +98,54 @@ func SearchEpisodesByEpisodesId(epiID string, client *http.Client) ([]byte, erro
 	baseURL := "https://api.bgm.tv/v0/episodes/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, epiID)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchEpisodesByEpisodesId", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
 	return jsonData, nil
 }
+
+/*
+GetAllEpisodesBySubjectID
+
+  - @brief 获取条目下某个类型的全部章节，会自动翻页。
+
+    API：/v0/episodes
+
+  - @param
+
+    【sbjID】：条目ID。
+
+    【typeName】：类型（只能是以下类型：本篇、特别篇、OP、ED、预告/宣传/广告、MAD、其他。如果不是以上的字符串，则全局搜索）
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]Episode和一个err。
+
+  - @retval []Episode是全部章节，err表示错误。如果err为nil，则没有错误。
+*/
+func GetAllEpisodesBySubjectID(sbjID, typeName string, client *http.Client) ([]Episode, error) {
+	const pageSize = 100
+	var episodes []Episode
+	for offset := 0; ; {
+		jsonData, err := SearchEpisodesByEpisodesName(sbjID, typeName, strconv.Itoa(pageSize), strconv.Itoa(offset), client)
+		if err != nil {
+			return nil, err
+		}
+		var page PagedEpisode
+		if err = json.Unmarshal(jsonData, &page); err != nil {
+			errMsg := errors.New("GetAllEpisodesBySubjectID：解析返回体失败")
+			return nil, errMsg
+		}
+		episodes = append(episodes, page.Data...)
+		offset += len(page.Data)
+		if offset >= page.Total {
+			break
+		}
+		if len(page.Data) == 0 {
+			errMsg := errors.New("GetAllEpisodesBySubjectID：返回的章节数少于total")
+			return nil, errMsg
+		}
+	}
+	return episodes, nil
+}
@@ -32,7 +32,7 @@ func SetIndices(client *http.Client) ([]byte, error) {
 
 	apiURL := "https://api.bgm.tv/v0/indices"
 
-	jsonData, err := getJsonDataFromURL("POST", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SetIndices", "POST", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +60,7 @@ func GetIndicesByID(idxID string, client *http.Client) ([]byte, error) {
 	baseURL := "https://api.bgm.tv/v0/indices/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, idxID)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("GetIndicesByID", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +95,7 @@ func EditIndicesInformationByIDAndRequestBody(idxID, requestBody string, client
 	baseURL := "https://api.bgm.tv/v0/indices/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, idxID)
 
-	jsonData, err := getJsonDataFromURL("PUT", apiURL, requestBody, client)
+	jsonData, err := getJsonDataFromURL("EditIndicesInformationByIDAndRequestBody", "PUT", apiURL, requestBody, client)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +148,7 @@ func GetIndicesSubjectByID(idxID, typeName, limit, offset string, client *http.C
 
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/indices/%s/subjects?%s", idxID, params.Encode())
 
-	err := getBoolDataFromURL("GET", apiURL, "", client)
+	err := getBoolDataFromURL("GetIndicesSubjectByID", "GET", apiURL, "", client)
 	if err != nil {
 		return false, err
 	}
@@ -183,7 +183,7 @@ AddSubjectsToIndicesByIDAndRequestBody
 func AddSubjectsToIndicesByIDAndRequestBody(idxID, requestBody string, client *http.Client) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/indices/%s/subjects", idxID)
 
-	err := getBoolDataFromURL("POST", apiURL, requestBody, client)
+	err := getBoolDataFromURL("AddSubjectsToIndicesByIDAndRequestBody", "POST", apiURL, requestBody, client)
 	if err != nil {
 		return false, err
 	}
@@ -219,7 +219,7 @@ EditSubjectsInformationInIndiesByIDAndRequestBody
 func EditSubjectsInformationInIndiesByIDAndRequestBody(idxID, subID, requestBody string, client *http.Client) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/indices/%s/subjects/%s", idxID, subID)
 
-	err := getBoolDataFromURL("PUT", apiURL, requestBody, client)
+	err := getBoolDataFromURL("EditSubjectsInformationInIndiesByIDAndRequestBody", "PUT", apiURL, requestBody, client)
 	if err != nil {
 		return false, err
 	}
@@ -248,7 +248,7 @@ DeleteSubjectsFromIndicesByID
 func DeleteSubjectsFromIndicesByID(idxID, subID string, client *http.Client) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/indices/%s/subjects/%s", idxID, subID)
 
-	err := getBoolDataFromURL("DELETE", apiURL, "", client)
+	err := getBoolDataFromURL("DeleteSubjectsFromIndicesByID", "DELETE", apiURL, "", client)
 	if err != nil {
 		return false, err
 	}
@@ -275,7 +275,7 @@ CollectIndicesForCurrentUserByID
 func CollectIndicesForCurrentUserByID(idxID string, client *http.Client) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/indices/%s/collect", idxID)
 
-	err := getBoolDataFromURL("POST", apiURL, "", client)
+	err := getBoolDataFromURL("CollectIndicesForCurrentUserByID", "POST", apiURL, "", client)
 	if err != nil {
 		return false, err
 	}
@@ -302,7 +302,7 @@ DeleteCollectIndicesForCurrentUserByID
 func DeleteCollectIndicesForCurrentUserByID(idxID string, client *http.Client) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/indices/%s/collect", idxID)
 
-	err := getBoolDataFromURL("DELETE", apiURL, "", client)
+	err := getBoolDataFromURL("DeleteCollectIndicesForCurrentUserByID", "DELETE", apiURL, "", client)
 	if err != nil {
 		return false, err
 	}
@@ -54,7 +54,7 @@ func SearchPersonsByName(limit, offset string, requestBody string, client *http.
 	params.Add("offset", fmt.Sprintf("%s", offset))
 
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-	jsonData, err := getJsonDataFromURL("POST", apiURL, requestBody, client)
+	jsonData, err := getJsonDataFromURL("SearchPersonsByName", "POST", apiURL, requestBody, client)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +82,33 @@ func SearchPersonsById(perID string, client *http.Client) ([]byte, error) {
 	baseURL := "https://api.bgm.tv/v0/persons/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, perID)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchPersonsById", "GET", apiURL, "", client)
+	if err != nil {
+		return nil, err
+	}
+	return jsonData, nil
+}
+
+/*
+SearchPersonsSubjectsById
+
+  - @brief 获取人物参与的条目。
+
+    API：/v0/persons/{person_id}/subjects
+
+  - @param
+
+    【perID】：人物ID。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]byte和一个err。
+
+  - @retval []byte是返回体，err表示错误。如果err为nil，则没有错误。
+*/
+func SearchPersonsSubjectsById(perID string, client *http.Client) ([]byte, error) {
+	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/persons/%s/subjects", perID)
+	jsonData, err := getJsonDataFromURL("SearchPersonsSubjectsById", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +136,7 @@ func SetCollectPersonsById(perID string, client *http.Client) (bool, error) {
 
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/persons/%s/collect", perID)
 
-	err := getBoolDataFromURL("POST", apiURL, "", client)
+	err := getBoolDataFromURL("SetCollectPersonsById", "POST", apiURL, "", client)
 	if err != nil {
 		return false, err
 	}
@@ -138,7 +164,7 @@ func DeleteCollectPersonsById(perID string, client *http.Client) (bool, error) {
 
 	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/persons/%s/collect/", perID)
 
-	err := getBoolDataFromURL("DELETE", apiURL, "", client)
+	err := getBoolDataFromURL("DeleteCollectPersonsById", "DELETE", apiURL, "", client)
 	if err != nil {
 		return false, err
 	}
@@ -42,7 +42,7 @@ func SearchPersonsRevisionsById(perID, limit, offset string, client *http.Client
 	params.Add("limit", fmt.Sprintf("%s", limit))
 	params.Add("offset", fmt.Sprintf("%s", offset))
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchPersonsRevisionsById", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +70,7 @@ func SearchPersonsRevisionsByRevisionsId(revID string, client *http.Client) ([]b
 	baseURL := "https://api.bgm.tv/v0/revisions/persons/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, revID)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchPersonsRevisionsByRevisionsId", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +106,7 @@ func SearchCharactersRevisionsById(chrID, limit, offset string, client *http.Cli
 	params.Add("offset", fmt.Sprintf("%s", offset))
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchCharactersRevisionsById", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +134,7 @@ func SearchCharactersRevisionsByRevisionsId(revID string, client *http.Client) (
 	baseURL := "https://api.bgm.tv/v0/revisions/characters/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, revID)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchCharactersRevisionsByRevisionsId", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +170,7 @@ func SearchSubjectsRevisionsById(subID, limit, offset string, client *http.Clien
 	params.Add("offset", fmt.Sprintf("%s", offset))
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchSubjectsRevisionsById", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -198,7 +198,7 @@ func SearchSubjectsRevisionsByRevisionsId(revID string, client *http.Client) ([]
 	baseURL := "https://api.bgm.tv/v0/revisions/subjects/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, revID)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchSubjectsRevisionsByRevisionsId", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +234,7 @@ func SearchEpisodesRevisionsById(epiID, limit, offset string, client *http.Clien
 	params.Add("offset", fmt.Sprintf("%s", offset))
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchEpisodesRevisionsById", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -262,7 +262,7 @@ func SearchEpisodesRevisionsByRevisionsId(revID string, client *http.Client) ([]
 	baseURL := "https://api.bgm.tv/v0/revisions/episodes/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, revID)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchEpisodesRevisionsByRevisionsId", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -9,6 +9,7 @@
 package lite_bangumi_api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -54,7 +55,7 @@ func SearchSubjectsByName(limit, offset string, requestBody string, client *http
 	params.Add("offset", fmt.Sprintf("%s", offset))
 
 	apiURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-	jsonData, err := getJsonDataFromURL("POST", apiURL, requestBody, client)
+	jsonData, err := getJsonDataFromURL("SearchSubjectsByName", "POST", apiURL, requestBody, client)
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +82,85 @@ SearchSubjectsById
 func SearchSubjectsById(subID string, client *http.Client) ([]byte, error) {
 	baseURL := "https://api.bgm.tv/v0/subjects/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, subID)
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchSubjectsById", "GET", apiURL, "", client)
+	if err != nil {
+		return nil, err
+	}
+	return jsonData, nil
+}
+
+/*
+SearchSubjectsRelationsById
+
+  - @brief 获取与条目相关的条目，如前传、续集、番外篇。
+
+    API：/v0/subjects/{subject_id}/subjects
+
+  - @param
+
+    【subID】：条目ID。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]byte和一个err。
+
+  - @retval []byte是返回体，err表示错误。如果err为nil，则没有错误。
+*/
+func SearchSubjectsRelationsById(subID string, client *http.Client) ([]byte, error) {
+	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/subjects/%s/subjects", subID)
+	jsonData, err := getJsonDataFromURL("SearchSubjectsRelationsById", "GET", apiURL, "", client)
+	if err != nil {
+		return nil, err
+	}
+	return jsonData, nil
+}
+
+/*
+SearchSubjectsPersonsById
+
+  - @brief 获取条目的制作人员和演出人员。
+
+    API：/v0/subjects/{subject_id}/persons
+
+  - @param
+
+    【subID】：条目ID。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]byte和一个err。
+
+  - @retval []byte是返回体，err表示错误。如果err为nil，则没有错误。
+*/
+func SearchSubjectsPersonsById(subID string, client *http.Client) ([]byte, error) {
+	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/subjects/%s/persons", subID)
+	jsonData, err := getJsonDataFromURL("SearchSubjectsPersonsById", "GET", apiURL, "", client)
+	if err != nil {
+		return nil, err
+	}
+	return jsonData, nil
+}
+
+/*
+SearchSubjectsCharactersById
+
+  - @brief 获取条目的角色和声优。
+
+    API：/v0/subjects/{subject_id}/characters
+
+  - @param
+
+    【subID】：条目ID。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]byte和一个err。
+
+  - @retval []byte是返回体，err表示错误。如果err为nil，则没有错误。
+*/
+func SearchSubjectsCharactersById(subID string, client *http.Client) ([]byte, error) {
+	apiURL := fmt.Sprintf("https://api.bgm.tv/v0/subjects/%s/characters", subID)
+	jsonData, err := getJsonDataFromURL("SearchSubjectsCharactersById", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -95,11 +174,11 @@ SearchAllSubjectsByName
 
     API：/search/subject/{keywords}
 
-  - @param 【keyWord】：关键字。
+  - @param 【keyWord】：关键字。可以包含空格、斜杠、问号等字符，会自动转义。
 
     【typeName】：条目类型（只能是以下字符串：书籍、动漫、音乐、游戏、三次元。如果typeName不满足以上字符串，则将全局搜索）
 
-    【responseGroup】：返回数据大小（只能指定为small、medium、large）
+    【responseGroup】：返回数据大小（只能指定为small、medium、large。如果不满足以上字符串，则会返回错误）
 
     【start】：开始的条目
 
@@ -112,9 +191,52 @@ SearchAllSubjectsByName
   - @retval []byte是返回体，err表示错误。如果err为nil，则没有错误。
 */
 func SearchAllSubjectsByName(keyWord, typeName, responseGroup, start, nmaxResults string, client *http.Client) ([]byte, error) {
+	return searchAllSubjects("SearchAllSubjectsByName", keyWord, typeName, ResponseGroup(responseGroup), start, nmaxResults, client)
+}
+
+/*
+SearchAllSubjectsByResponseGroup
+
+  - @brief 与SearchAllSubjectsByName相同，responseGroup使用ResponseGroup类型。
+
+    API：/search/subject/{keywords}
+
+  - @param 【responseGroup】：返回数据大小（只能指定为ResponseGroupSmall、ResponseGroupMedium、ResponseGroupLarge。如果不满足以上值，则会返回错误）
+
+    其他参数与SearchAllSubjectsByName相同。
+
+  - @return 返回一个[]byte和一个err。
+
+  - @retval []byte是返回体，err表示错误。如果err为nil，则没有错误。
+*/
+func SearchAllSubjectsByResponseGroup(keyWord, typeName string, responseGroup ResponseGroup, start, nmaxResults string, client *http.Client) ([]byte, error) {
+	return searchAllSubjects("SearchAllSubjectsByResponseGroup", keyWord, typeName, responseGroup, start, nmaxResults, client)
+}
+
+/*
+searchAllSubjects
+
+  - @brief SearchAllSubjectsByName和SearchAllSubjectsByResponseGroup的实现
+
+  - @param
+
+    【op】：操作名，用于日志和拦截器
+
+    其他参数与SearchAllSubjectsByResponseGroup相同
+
+  - @return 返回一个[]byte和一个err。
+*/
+func searchAllSubjects(op, keyWord, typeName string, responseGroup ResponseGroup, start, nmaxResults string, client *http.Client) ([]byte, error) {
 	baseURL := "https://api.bgm.tv/search/subject/"
 	params := url.Values{}
 
+	switch responseGroup {
+	case ResponseGroupSmall, ResponseGroupMedium, ResponseGroupLarge:
+	default:
+		errMsg := errors.New("不匹配的responseGroup")
+		return nil, errMsg
+	}
+
 	sType := 0
 	switch typeName {
 	case "书籍":
@@ -135,9 +257,9 @@ func SearchAllSubjectsByName(keyWord, typeName, responseGroup, start, nmaxResult
 	params.Add("responseGroup", fmt.Sprintf("%s", responseGroup))
 	params.Add("start", fmt.Sprintf("%s", start))
 	params.Add("max_results", fmt.Sprintf("%s", nmaxResults))
-	apiURL := fmt.Sprintf("%s%s?%s", baseURL, keyWord, params.Encode())
+	apiURL := fmt.Sprintf("%s%s?%s", baseURL, url.PathEscape(keyWord), params.Encode())
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL(op, "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +284,7 @@ GetCalender
 func GetCalender(client *http.Client) ([]byte, error) {
 	apiURL := "https://api.bgm.tv/calendar"
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("GetCalender", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +34,7 @@ func SearchUserNameByName(userName string, client *http.Client) ([]byte, error)
 	baseURL := "https://api.bgm.tv/v0/users/"
 	apiURL := fmt.Sprintf("%s%s", baseURL, userName)
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("SearchUserNameByName", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +59,7 @@ GetMe
 func GetMe(client *http.Client) ([]byte, error) {
 	apiURL := "https://api.bgm.tv/v0/me"
 
-	jsonData, err := getJsonDataFromURL("GET", apiURL, "", client)
+	jsonData, err := getJsonDataFromURL("GetMe", "GET", apiURL, "", client)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,423 @@
+/**
+ * @file 	bbcode.go
+ * @brief 	Bangumi BBCode（条目简介、收藏吐槽、目录描述等）的解析和渲染
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+ * @brief BBCode节点类型
+ */
+type BBCodeNodeType int
+
+const (
+	BBCodeRoot   BBCodeNodeType = iota // 根节点
+	BBCodeText                         // 文本
+	BBCodeTag                          // 标签，如[b]...[/b]
+	BBCodeSmiley                       // 表情，如(bgm38)
+)
+
+/*
+BBCodeNode
+
+  - @brief BBCode语法树的节点。
+
+    【Type】：节点类型。
+
+    【Tag】：标签名，小写，如"b"、"url"。
+
+    【Attr】：标签参数，如[url=...]中的地址。
+
+    【Text】：文本节点的内容，或表情的原文。
+
+    【Children】：子节点。
+*/
+type BBCodeNode struct {
+	Type     BBCodeNodeType
+	Tag      string
+	Attr     string
+	Text     string
+	Children []*BBCodeNode
+}
+
+/*
+ * @brief 支持的标签。code中的内容不再解析
+ */
+var bbcodeTags = map[string]bool{
+	"b": true, "i": true, "u": true, "s": true,
+	"url": true, "img": true, "mask": true, "quote": true, "code": true,
+	"color": true, "size": true, "center": true, "left": true, "right": true,
+}
+
+var (
+	bbcodeTagPattern    = regexp.MustCompile(`^\[(/?)([a-zA-Z]+)(?:=([^\]\n]*))?\]`)
+	bbcodeSmileyPattern = regexp.MustCompile(`^\(bgm(\d{2,3})\)`)
+	bbcodeColorPattern  = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|[a-zA-Z]{1,20})$`)
+)
+
+/*
+ParseBBCode
+
+  - @brief 解析BBCode文本。不支持的标签、没有闭合的标签和多余的结束标签按原文保留为文本，因此不会失败。
+
+  - @param
+
+    【text】：BBCode文本，如条目的summary。
+
+  - @return 返回语法树的根节点。
+*/
+func ParseBBCode(text string) *BBCodeNode {
+	root := &BBCodeNode{Type: BBCodeRoot}
+	stack := []*BBCodeNode{root}
+	// 开始标签的原文，用于没有闭合时还原
+	var opens []string
+
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		top.Children = append(top.Children, &BBCodeNode{Type: BBCodeText, Text: buf.String()})
+		buf.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch text[i] {
+		case '[':
+			m := bbcodeTagPattern.FindStringSubmatch(rest)
+			if m == nil || !bbcodeTags[strings.ToLower(m[2])] {
+				break
+			}
+			name := strings.ToLower(m[2])
+			if m[1] == "" {
+				flush()
+				node := &BBCodeNode{Type: BBCodeTag, Tag: name, Attr: strings.TrimSpace(m[3])}
+				top := stack[len(stack)-1]
+				top.Children = append(top.Children, node)
+				i += len(m[0])
+				if name == "code" {
+					// code中的内容按原文保存
+					end := indexBBCodeClose(text[i:], "code")
+					if end < 0 {
+						top.Children = top.Children[:len(top.Children)-1]
+						buf.WriteString(m[0])
+						continue
+					}
+					if end > 0 {
+						node.Children = []*BBCodeNode{{Type: BBCodeText, Text: text[i : i+end]}}
+					}
+					i += end + len("[/code]")
+					continue
+				}
+				stack = append(stack, node)
+				opens = append(opens, m[0])
+				continue
+			}
+			// 结束标签，跳过中间没有闭合的标签
+			depth := -1
+			for d := len(stack) - 1; d > 0; d-- {
+				if stack[d].Tag == name {
+					depth = d
+					break
+				}
+			}
+			if depth < 0 {
+				break
+			}
+			flush()
+			for len(stack)-1 > depth {
+				unwrapBBCodeNode(stack[len(stack)-2], opens[len(opens)-1])
+				stack = stack[:len(stack)-1]
+				opens = opens[:len(opens)-1]
+			}
+			stack = stack[:len(stack)-1]
+			opens = opens[:len(opens)-1]
+			i += len(m[0])
+			continue
+		case '(':
+			if m := bbcodeSmileyPattern.FindStringSubmatch(rest); m != nil && bbcodeSmileyURL(m[1]) != "" {
+				flush()
+				top := stack[len(stack)-1]
+				top.Children = append(top.Children, &BBCodeNode{Type: BBCodeSmiley, Text: m[0]})
+				i += len(m[0])
+				continue
+			}
+		}
+		buf.WriteByte(text[i])
+		i++
+	}
+	flush()
+
+	// 没有闭合的标签还原为文本
+	for len(stack) > 1 {
+		unwrapBBCodeNode(stack[len(stack)-2], opens[len(opens)-1])
+		stack = stack[:len(stack)-1]
+		opens = opens[:len(opens)-1]
+	}
+	return root
+}
+
+/*
+indexBBCodeClose
+
+  - @brief 不区分大小写地查找结束标签。在原文上逐个比较，不转换大小写，因此返回的位置可以直接用于原文
+
+  - @param
+
+    【text】：文本
+
+    【name】：标签名，小写
+
+  - @return 返回结束标签在text中的位置，没有找到时返回-1。
+*/
+func indexBBCodeClose(text, name string) int {
+	closeTag := "[/" + name + "]"
+	for j := 0; j+len(closeTag) <= len(text); j++ {
+		if text[j] == '[' && text[j+1] == '/' && strings.EqualFold(text[j:j+len(closeTag)], closeTag) {
+			return j
+		}
+	}
+	return -1
+}
+
+/*
+unwrapBBCodeNode
+
+  - @brief 将parent的最后一个子节点（没有闭合的标签）替换为开始标签原文和它的子节点
+
+  - @param
+
+    【parent】：父节点
+
+    【open】：开始标签的原文
+*/
+func unwrapBBCodeNode(parent *BBCodeNode, open string) {
+	last := len(parent.Children) - 1
+	node := parent.Children[last]
+	children := append([]*BBCodeNode{{Type: BBCodeText, Text: open}}, node.Children...)
+	parent.Children = append(parent.Children[:last], children...)
+}
+
+/*
+bbcodeSmileyURL
+
+  - @brief 获取表情图片的地址
+
+  - @param
+
+    【num】：(bgmNN)中的数字
+
+  - @return 返回图片地址，不存在的表情返回空字符串。
+*/
+func bbcodeSmileyURL(num string) string {
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 1 || n > 125 {
+		return ""
+	}
+	if n <= 23 {
+		ext := ".png"
+		if n == 11 || n == 23 {
+			ext = ".gif"
+		}
+		return fmt.Sprintf("https://lain.bgm.tv/img/smiles/bgm/%02d%s", n, ext)
+	}
+	return fmt.Sprintf("https://lain.bgm.tv/img/smiles/tv/%02d.gif", n-23)
+}
+
+/*
+safeBBCodeURL
+
+  - @brief 检查链接地址，只允许http和https
+
+  - @param
+
+    【raw】：地址
+
+  - @return 返回地址和是否安全。
+*/
+func safeBBCodeURL(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return "", false
+	}
+	return u.String(), true
+}
+
+/*
+HTML
+
+  - @brief 渲染为HTML。文本会被转义，链接和图片只允许http和https地址，颜色和字号会被检查，不合法的参数会被忽略。
+    没有文字的链接以地址为文字。
+
+  - @return 返回HTML文本。
+*/
+func (n *BBCodeNode) HTML() string {
+	var b strings.Builder
+	n.writeHTML(&b)
+	return b.String()
+}
+
+func (n *BBCodeNode) writeHTML(b *strings.Builder) {
+	children := func() {
+		for _, child := range n.Children {
+			child.writeHTML(b)
+		}
+	}
+	wrap := func(open, close string) {
+		b.WriteString(open)
+		children()
+		b.WriteString(close)
+	}
+
+	switch n.Type {
+	case BBCodeRoot:
+		children()
+	case BBCodeText:
+		b.WriteString(strings.ReplaceAll(html.EscapeString(n.Text), "\n", "<br>\n"))
+	case BBCodeSmiley:
+		num := strings.TrimSuffix(strings.TrimPrefix(n.Text, "(bgm"), ")")
+		b.WriteString(`<img class="smile" src="` + html.EscapeString(bbcodeSmileyURL(num)) + `" alt="` + html.EscapeString(n.Text) + `">`)
+	case BBCodeTag:
+		switch n.Tag {
+		case "b":
+			wrap("<strong>", "</strong>")
+		case "i":
+			wrap("<em>", "</em>")
+		case "u":
+			wrap("<u>", "</u>")
+		case "s":
+			wrap("<del>", "</del>")
+		case "mask":
+			wrap(`<span class="text_mask">`, "</span>")
+		case "quote":
+			wrap("<blockquote>", "</blockquote>")
+		case "code":
+			b.WriteString("<pre><code>" + html.EscapeString(n.innerText()) + "</code></pre>")
+		case "center", "left", "right":
+			wrap(`<div style="text-align:`+n.Tag+`">`, "</div>")
+		case "color":
+			if bbcodeColorPattern.MatchString(n.Attr) {
+				wrap(`<span style="color:`+n.Attr+`">`, "</span>")
+			} else {
+				children()
+			}
+		case "size":
+			size, err := strconv.Atoi(n.Attr)
+			if err != nil {
+				children()
+				break
+			}
+			if size < 8 {
+				size = 8
+			} else if size > 50 {
+				size = 50
+			}
+			wrap(`<span style="font-size:`+strconv.Itoa(size)+`px">`, "</span>")
+		case "url":
+			href := n.Attr
+			if len(href) == 0 {
+				href = n.innerText()
+			}
+			u, ok := safeBBCodeURL(href)
+			switch {
+			case ok && len(n.Children) == 0:
+				b.WriteString(`<a href="` + html.EscapeString(u) + `" rel="nofollow noopener noreferrer" target="_blank">` + html.EscapeString(n.Attr) + "</a>")
+			case ok:
+				wrap(`<a href="`+html.EscapeString(u)+`" rel="nofollow noopener noreferrer" target="_blank">`, "</a>")
+			case len(n.Children) == 0:
+				b.WriteString(html.EscapeString(n.Attr))
+			default:
+				children()
+			}
+		case "img":
+			if u, ok := safeBBCodeURL(n.innerText()); ok {
+				b.WriteString(`<img src="` + html.EscapeString(u) + `" alt="" referrerpolicy="no-referrer">`)
+			}
+		default:
+			children()
+		}
+	}
+}
+
+/*
+PlainText
+
+  - @brief 渲染为纯文本，去掉全部标签。图片被去掉，没有文字的链接输出地址，表情输出原文。
+
+  - @return 返回纯文本。
+*/
+func (n *BBCodeNode) PlainText() string {
+	var b strings.Builder
+	n.writePlainText(&b)
+	return b.String()
+}
+
+func (n *BBCodeNode) writePlainText(b *strings.Builder) {
+	switch {
+	case n.Type == BBCodeText || n.Type == BBCodeSmiley:
+		b.WriteString(n.Text)
+	case n.Type == BBCodeTag && n.Tag == "img":
+	case n.Type == BBCodeTag && n.Tag == "url" && len(n.Children) == 0:
+		b.WriteString(n.Attr)
+	default:
+		for _, child := range n.Children {
+			child.writePlainText(b)
+		}
+	}
+}
+
+/*
+ * @brief 子节点的文本，用于[img]和没有参数的[url]中的地址
+ */
+func (n *BBCodeNode) innerText() string {
+	var b strings.Builder
+	for _, child := range n.Children {
+		child.writePlainText(&b)
+	}
+	return b.String()
+}
+
+/*
+BBCodeToHTML
+
+  - @brief 将BBCode文本渲染为HTML，与ParseBBCode(text).HTML()相同。
+
+  - @param
+
+    【text】：BBCode文本。
+
+  - @return 返回HTML文本。
+*/
+func BBCodeToHTML(text string) string {
+	return ParseBBCode(text).HTML()
+}
+
+/*
+BBCodeToPlainText
+
+  - @brief 将BBCode文本渲染为纯文本，与ParseBBCode(text).PlainText()相同。
+
+  - @param
+
+    【text】：BBCode文本。
+
+  - @return 返回纯文本。
+*/
+func BBCodeToPlainText(text string) string {
+	return ParseBBCode(text).PlainText()
+}
@@ -0,0 +1,102 @@
+package lite_bangumi_api
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBBCodeToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain text is escaped", `<script>alert("x")</script> & co`, `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; co`},
+		{"newlines", "a\nb", "a<br>\nb"},
+		{"basic tags", "[b]b[/b][i]i[/i][u]u[/u][s]s[/s]", "<strong>b</strong><em>i</em><u>u</u><del>s</del>"},
+		{"case-insensitive tags", "[B]x[/b][Url=https://a.com]y[/URL]", `<strong>x</strong><a href="https://a.com" rel="nofollow noopener noreferrer" target="_blank">y</a>`},
+		{"nested tags", "[b][i]x[/i][/b]", "<strong><em>x</em></strong>"},
+		{"multibyte text in tags", "[b]魔法少女まどか☆マギカ[/b]", "<strong>魔法少女まどか☆マギカ</strong>"},
+		{"unknown tag kept as text", "[foo]x[/foo]", "[foo]x[/foo]"},
+		{"unclosed tag kept as text", "[b]x", "[b]x"},
+		{"stray closing tag kept as text", "x[/b]", "x[/b]"},
+		{"misnested tags", "[b][i]x[/b]", "<strong>[i]x</strong>"},
+		{"mask and quote", "[mask]m[/mask][quote]q[/quote]", `<span class="text_mask">m</span><blockquote>q</blockquote>`},
+		{"alignment", "[center]c[/center]", `<div style="text-align:center">c</div>`},
+		{"code is not parsed", "[code][b]x[/b] <y>[/code]", "<pre><code>[b]x[/b] &lt;y&gt;</code></pre>"},
+		{"code with uppercase closing tag", "[code]x[/CODE]tail", "<pre><code>x</code></pre>tail"},
+		{"code with multibyte content", "[code]İİİİ[/code]tail", "<pre><code>İİİİ</code></pre>tail"},
+		{"code with content that changes length when lowercased", "[code]ẞẞ[/code]tail", "<pre><code>ẞẞ</code></pre>tail"},
+		{"unclosed code kept as text", "[code]x", "[code]x"},
+		{"empty code", "[code][/code]", "<pre><code></code></pre>"},
+		{"url without attribute", "[url]https://bgm.tv/subject/1[/url]", `<a href="https://bgm.tv/subject/1" rel="nofollow noopener noreferrer" target="_blank">https://bgm.tv/subject/1</a>`},
+		{"url without text shows address", "[url=http://a.com][/url]", `<a href="http://a.com" rel="nofollow noopener noreferrer" target="_blank">http://a.com</a>`},
+		{"javascript url dropped", "[url=javascript:alert(1)]x[/url]", "x"},
+		{"javascript url without text", "[url=javascript:alert(1)][/url]", "javascript:alert(1)"},
+		{"relative url dropped", "[url=/subject/1]x[/url]", "x"},
+		{"url attribute injection", `[url=http://a.com/" onclick="alert(1)]x[/url]`, `<a href="http://a.com/%22%20onclick=%22alert%281%29" rel="nofollow noopener noreferrer" target="_blank">x</a>`},
+		{"image", "[img]https://lain.bgm.tv/a.jpg[/img]", `<img src="https://lain.bgm.tv/a.jpg" alt="" referrerpolicy="no-referrer">`},
+		{"javascript image dropped", "[img]javascript:alert(1)[/img]", ""},
+		{"color", "[color=#FF0000]x[/color][color=red]y[/color]", `<span style="color:#FF0000">x</span><span style="color:red">y</span>`},
+		{"color injection rejected", "[color=red;background:url(x)]x[/color]", "x"},
+		{"size clamped", "[size=100]x[/size][size=1]y[/size]", `<span style="font-size:50px">x</span><span style="font-size:8px">y</span>`},
+		{"invalid size ignored", `[size=10px" onmouseover="x]x[/size]`, "x"},
+		{"smiley", "(bgm38)", `<img class="smile" src="https://lain.bgm.tv/img/smiles/tv/15.gif" alt="(bgm38)">`},
+		{"first bgm smiley", "(bgm01)", `<img class="smile" src="https://lain.bgm.tv/img/smiles/bgm/01.png" alt="(bgm01)">`},
+		{"gif bgm smiley", "(bgm23)", `<img class="smile" src="https://lain.bgm.tv/img/smiles/bgm/23.gif" alt="(bgm23)">`},
+		{"first tv smiley", "(bgm24)", `<img class="smile" src="https://lain.bgm.tv/img/smiles/tv/01.gif" alt="(bgm24)">`},
+		{"three-digit tv smiley", "(bgm123)", `<img class="smile" src="https://lain.bgm.tv/img/smiles/tv/100.gif" alt="(bgm123)">`},
+		{"last tv smiley", "(bgm125)", `<img class="smile" src="https://lain.bgm.tv/img/smiles/tv/102.gif" alt="(bgm125)">`},
+		{"smiley out of range kept as text", "(bgm126)", "(bgm126)"},
+		{"unknown smiley kept as text", "(bgm999)", "(bgm999)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BBCodeToHTML(tt.text)
+			if got != tt.want {
+				t.Errorf("BBCodeToHTML(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("BBCodeToHTML(%q) is not valid UTF-8", tt.text)
+			}
+		})
+	}
+}
+
+func TestBBCodeToPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"tags removed", "[b]a[/b][color=red]b[/color]", "ab"},
+		{"url text", "[url=https://a.com]link[/url]", "link"},
+		{"url without text shows address", "[url=http://a.com][/url]", "http://a.com"},
+		{"url without attribute", "[url]https://a.com[/url]", "https://a.com"},
+		{"image removed", "x[img]https://a.com/a.jpg[/img]y", "xy"},
+		{"smiley kept", "(bgm38)", "(bgm38)"},
+		{"unclosed tag kept", "[b]x", "[b]x"},
+		{"code with multibyte content", "[code]İİ[b][/b][/code]tail", "İİ[b][/b]tail"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BBCodeToPlainText(tt.text); got != tt.want {
+				t.Errorf("BBCodeToPlainText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBBCodeTree(t *testing.T) {
+	root := ParseBBCode("[URL=https://a.com]x[/url](bgm38)")
+	if len(root.Children) != 2 {
+		t.Fatalf("children = %d, want 2", len(root.Children))
+	}
+	link := root.Children[0]
+	if link.Type != BBCodeTag || link.Tag != "url" || link.Attr != "https://a.com" {
+		t.Errorf("link = %+v", link)
+	}
+	if smiley := root.Children[1]; smiley.Type != BBCodeSmiley || smiley.Text != "(bgm38)" {
+		t.Errorf("smiley = %+v", smiley)
+	}
+}
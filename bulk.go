@@ -0,0 +1,338 @@
+/**
+ * @file 	bulk.go
+ * @brief 	并发批量获取条目、角色、人物、章节
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+/*
+ * @brief 默认并发数
+ */
+const defaultBulkConcurrency = 4
+
+/*
+BulkOptions
+
+  - @brief 批量获取的选项。请求仍然会经过RateLimit限速。
+
+    【Concurrency】：最大并发数，小于等于0时为4。
+
+    【Client】：http.Client对象，为nil时使用http.DefaultClient。
+
+    【Progress】：进度回调，每获取完一个ID调用一次，done为已完成数，total为去重后的总数。回调不会并发调用。
+*/
+type BulkOptions struct {
+	Concurrency int
+	Client      *http.Client
+	Progress    func(done, total int)
+}
+
+/*
+BulkResult
+
+  - @brief 批量获取中单个ID的结果。
+
+    【Data】：返回体，与对应的单个API函数相同。
+
+    【Err】：错误，如果Err为nil，则没有错误。
+*/
+type BulkResult struct {
+	Data []byte
+	Err  error
+}
+
+/*
+ * @brief 把context绑定到http.Client上，通过该client发送的请求会使用这个context，取消后正在进行的请求和限速等待都会中止
+ */
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+/*
+ * @brief 请求的context中保存绑定它的contextTransport，用于判断请求是否已经使用了绑定的context
+ */
+type contextTransportKey struct{}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Context().Value(contextTransportKey{}) == t {
+		return base.RoundTrip(req)
+	}
+
+	// 请求不是由requestContext创建的（如client被外层再次包装），在这里绑定ctx，响应体关闭后解除绑定
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+/*
+ * @brief 关闭时调用release的响应体
+ */
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+/*
+clientWithContext
+
+  - @brief 复制client并绑定ctx，client已经绑定了ctx时直接返回
+
+  - @param
+
+    【ctx】：context
+
+    【client】：http.Client对象
+
+  - @return 返回一个新的*http.Client。
+*/
+func clientWithContext(ctx context.Context, client *http.Client) *http.Client {
+	if t, ok := client.Transport.(*contextTransport); ok && t.ctx == ctx {
+		return client
+	}
+	c := *client
+	c.Transport = &contextTransport{ctx: ctx, base: client.Transport}
+	return &c
+}
+
+/*
+requestContext
+
+  - @brief 获取创建请求时使用的context，client没有绑定context时返回context.Background()
+
+  - @param
+
+    【client】：http.Client对象
+
+  - @return 返回一个context.Context。
+*/
+func requestContext(client *http.Client) context.Context {
+	if client != nil {
+		if t, ok := client.Transport.(*contextTransport); ok {
+			return context.WithValue(t.ctx, contextTransportKey{}, t)
+		}
+	}
+	return context.Background()
+}
+
+/*
+bulkDo
+
+  - @brief 使用有限的worker对0～n-1并发调用do。ctx被取消后未开始的下标不再调用do
+
+  - @param
+
+    【ctx】：context，会绑定到传给do的client上
+
+    【n】：任务数
+
+    【opts】：选项，可以为nil
+
+    【do】：单个任务，client为绑定了ctx的http.Client对象
+
+  - @return 返回每个下标是否调用了do。
+*/
+func bulkDo(ctx context.Context, n int, opts *BulkOptions, do func(i int, client *http.Client)) []bool {
+	concurrency := defaultBulkConcurrency
+	client := http.DefaultClient
+	var progress func(done, total int)
+	if opts != nil {
+		progress = opts.Progress
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
+		if opts.Client != nil {
+			client = opts.Client
+		}
+	}
+	client = clientWithContext(ctx, client)
+
+	started := make([]bool, n)
+	done := 0
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+
+	for w := 0; w < concurrency && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() == nil {
+					started[i] = true
+					do(i, client)
+				}
+				mu.Lock()
+				done++
+				if progress != nil {
+					progress(done, n)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return started
+}
+
+/*
+bulkFetch
+
+  - @brief 使用有限的worker并发调用fetch，重复的ID只获取一次
+
+  - @param
+
+    【ctx】：context，取消后未开始的ID会返回ctx.Err()，正在进行的请求和限速等待会中止
+
+    【ids】：ID列表
+
+    【opts】：选项，可以为nil
+
+    【fetch】：单个ID的API函数
+
+  - @return 返回以ID为键的结果。
+*/
+func bulkFetch(ctx context.Context, ids []int, opts *BulkOptions, fetch func(id string, client *http.Client) ([]byte, error)) map[int]BulkResult {
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	list := make([]BulkResult, len(unique))
+	started := bulkDo(ctx, len(unique), opts, func(i int, client *http.Client) {
+		list[i].Data, list[i].Err = fetch(strconv.Itoa(unique[i]), client)
+	})
+
+	results := make(map[int]BulkResult, len(unique))
+	for i, id := range unique {
+		if !started[i] {
+			list[i].Err = ctx.Err()
+		}
+		results[id] = list[i]
+	}
+	return results
+}
+
+/*
+GetSubjects
+
+  - @brief 并发获取多个条目，相当于对每个ID调用SearchSubjectsById。
+
+    API：/v0/subjects/{subject_id}
+
+  - @param
+
+    【ctx】：context。
+
+    【ids】：条目ID列表，重复的ID只获取一次。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回以条目ID为键的map[int]BulkResult。
+*/
+func GetSubjects(ctx context.Context, ids []int, opts *BulkOptions) map[int]BulkResult {
+	return bulkFetch(ctx, ids, opts, SearchSubjectsById)
+}
+
+/*
+GetCharacters
+
+  - @brief 并发获取多个角色，相当于对每个ID调用SearchCharactersById。
+
+    API：/v0/characters/{character_id}
+
+  - @param
+
+    【ctx】：context。
+
+    【ids】：角色ID列表，重复的ID只获取一次。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回以角色ID为键的map[int]BulkResult。
+*/
+func GetCharacters(ctx context.Context, ids []int, opts *BulkOptions) map[int]BulkResult {
+	return bulkFetch(ctx, ids, opts, SearchCharactersById)
+}
+
+/*
+GetPersons
+
+  - @brief 并发获取多个人物，相当于对每个ID调用SearchPersonsById。
+
+    API：/v0/persons/{person_id}
+
+  - @param
+
+    【ctx】：context。
+
+    【ids】：人物ID列表，重复的ID只获取一次。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回以人物ID为键的map[int]BulkResult。
+*/
+func GetPersons(ctx context.Context, ids []int, opts *BulkOptions) map[int]BulkResult {
+	return bulkFetch(ctx, ids, opts, SearchPersonsById)
+}
+
+/*
+GetEpisodes
+
+  - @brief 并发获取多个章节，相当于对每个ID调用SearchEpisodesByEpisodesId。
+
+    API：/v0/episodes/{episode_id}
+
+  - @param
+
+    【ctx】：context。
+
+    【ids】：章节ID列表，重复的ID只获取一次。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回以章节ID为键的map[int]BulkResult。
+*/
+func GetEpisodes(ctx context.Context, ids []int, opts *BulkOptions) map[int]BulkResult {
+	return bulkFetch(ctx, ids, opts, SearchEpisodesByEpisodesId)
+}
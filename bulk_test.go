@@ -0,0 +1,167 @@
+package lite_bangumi_api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBulkFetchDedupe(t *testing.T) {
+	api := newMockAPI(t, func(req *http.Request) (int, string) {
+		id := strings.TrimPrefix(req.URL.Path, "/v0/subjects/")
+		if id == "404" {
+			return http.StatusNotFound, `{}`
+		}
+		return http.StatusOK, `{"id":` + id + `}`
+	})
+
+	var mu sync.Mutex
+	var calls [][2]int
+	results := GetSubjects(context.Background(), []int{1, 2, 2, 404, 1}, &BulkOptions{
+		Concurrency: 2,
+		Progress: func(done, total int) {
+			mu.Lock()
+			calls = append(calls, [2]int{done, total})
+			mu.Unlock()
+		},
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	if n := api.count("GET /v0/subjects/"); n != 3 {
+		t.Errorf("requests = %d, want 3", n)
+	}
+	for _, id := range []int{1, 2} {
+		if r := results[id]; r.Err != nil || string(r.Data) != `{"id":`+map[int]string{1: "1", 2: "2"}[id]+`}` {
+			t.Errorf("results[%d] = %q, %v", id, r.Data, r.Err)
+		}
+	}
+	if results[404].Err == nil {
+		t.Error("results[404].Err = nil, want error")
+	}
+	if len(calls) != 3 || calls[2] != [2]int{3, 3} {
+		t.Errorf("progress calls = %v, want 3 calls ending with {3 3}", calls)
+	}
+}
+
+func TestBulkFetchConcurrency(t *testing.T) {
+	var inFlight, peak int32
+	newMockAPI(t, func(req *http.Request) (int, string) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return http.StatusOK, `{}`
+	})
+
+	tests := []struct {
+		name        string
+		concurrency int
+		want        int32
+	}{
+		{"explicit", 3, 3},
+		{"default", 0, defaultBulkConcurrency},
+		{"serial", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&peak, 0)
+			ids := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			GetCharacters(context.Background(), ids, &BulkOptions{Concurrency: tt.concurrency})
+			if p := atomic.LoadInt32(&peak); p > tt.want || p == 0 {
+				t.Errorf("peak concurrency = %d, want 1..%d", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkFetchCancelled(t *testing.T) {
+	api := newMockAPI(t, func(req *http.Request) (int, string) { return http.StatusOK, `{}` })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := GetPersons(ctx, []int{1, 2, 3}, nil)
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	for id, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("results[%d].Err = %v, want context.Canceled", id, r.Err)
+		}
+	}
+	if n := api.count("GET"); n != 0 {
+		t.Errorf("requests = %d, want 0", n)
+	}
+}
+
+func TestBulkFetchCancelsRateLimitWait(t *testing.T) {
+	// 限速在拦截器内侧，因此不使用mockAPI，直接替换Transport
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+	})}
+	saved := RateLimit
+	RateLimit = NewRateLimiter(time.Hour)
+	defer func() { RateLimit = saved }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	results := GetEpisodes(ctx, []int{1, 2, 3}, &BulkOptions{Concurrency: 3, Client: client})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("GetEpisodes took %v, want it to stop at the deadline", elapsed)
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	// 第一个请求不需要等待，其余的请求在限速等待中被取消
+	if failed < 2 {
+		t.Errorf("failed = %d, want at least 2", failed)
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestContextTransportBindsUnboundRequests(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	client := clientWithContext(ctx, &http.Client{Transport: base})
+	if clientWithContext(ctx, client) != client {
+		t.Error("clientWithContext rebinds a client already bound to the same ctx")
+	}
+
+	// 不经过requestContext创建的请求也会在取消后中止
+	req, _ := http.NewRequest("GET", "https://api.bgm.tv/v0/subjects/1", nil)
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Transport.RoundTrip(req)
+		done <- err
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("RoundTrip error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RoundTrip was not cancelled")
+	}
+}
@@ -0,0 +1,381 @@
+/**
+ * @file 	collaboration.go
+ * @brief 	人物之间的合作关系图
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+CollaborationOptions
+
+  - @brief 生成合作关系图的选项。
+
+    【SubjectTypes】：只统计这些类型的条目，为空时统计全部条目。
+
+    【Relations】：只统计担任这些职位的合作者，如"导演"、"原画"，为空时统计全部职位。
+
+    【MinShared】：合作者至少共同参与的条目数，小于等于0时为1。
+
+    【MaxPersons】：最多保留的合作者数（按共同参与的条目数），小于等于0时不限制。
+
+    【Concurrency】：最大并发数，小于等于0时为4。请求仍然会经过RateLimit限速。
+
+    【Client】：http.Client对象，为nil时使用http.DefaultClient。
+*/
+type CollaborationOptions struct {
+	SubjectTypes []int
+	Relations    []string
+	MinShared    int
+	MaxPersons   int
+	Concurrency  int
+	Client       *http.Client
+}
+
+/*
+CollaborationPerson
+
+  - @brief 关系图中的人物。
+
+    【Shared】：与中心人物共同参与的条目数，中心人物为参与的条目数。
+
+    【Relations】：在共同参与的条目中担任的职位和次数。
+*/
+type CollaborationPerson struct {
+	ID        int            `json:"id"`
+	Name      string         `json:"name"`
+	Career    []string       `json:"career"`
+	Shared    int            `json:"shared"`
+	Relations map[string]int `json:"relations"`
+}
+
+/*
+ * @brief 两个人物之间的合作，Weight为共同参与的条目数，From < To
+ */
+type CollaborationEdge struct {
+	From     int   `json:"from"`
+	To       int   `json:"to"`
+	Weight   int   `json:"weight"`
+	Subjects []int `json:"subjects"`
+}
+
+/*
+CollaborationGraph
+
+  - @brief 以一个人物为中心的合作关系图。
+
+    【PersonID】：中心人物ID。
+
+    【Persons】：以人物ID为键的人物，包括中心人物。
+
+    【Edges】：人物之间的合作，包括合作者之间的合作，按Weight从大到小排序。
+
+    【Subjects】：统计的条目ID和名称。
+
+    【Errors】：获取人物失败的条目和错误。
+*/
+type CollaborationGraph struct {
+	PersonID int
+	Persons  map[int]*CollaborationPerson
+	Edges    []CollaborationEdge
+	Subjects map[int]string
+	Errors   map[int]error
+}
+
+/*
+BuildCollaborationGraph
+
+  - @brief 统计一个人物最常合作的人物。获取人物参与的全部条目，再并发获取每个条目的人物，
+    两个人物同时出现在一个条目中即为一次合作。
+
+    API：/v0/persons/{person_id}/subjects、/v0/subjects/{subject_id}/persons
+
+  - @param
+
+    【ctx】：context。
+
+    【personID】：中心人物ID。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回一个*CollaborationGraph和一个err。
+
+  - @retval 只有获取中心人物参与的条目失败时返回err，单个条目的错误记录在CollaborationGraph.Errors中。
+*/
+func BuildCollaborationGraph(ctx context.Context, personID int, opts *CollaborationOptions) (*CollaborationGraph, error) {
+	if opts == nil {
+		opts = &CollaborationOptions{}
+	}
+	minShared := opts.MinShared
+	if minShared <= 0 {
+		minShared = 1
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	jsonData, err := SearchPersonsSubjectsById(strconv.Itoa(personID), client)
+	if err != nil {
+		return nil, err
+	}
+	var works []PersonRelatedSubject
+	if err = json.Unmarshal(jsonData, &works); err != nil {
+		errMsg := errors.New("BuildCollaborationGraph：解析人物参与的条目失败")
+		return nil, errMsg
+	}
+
+	types := make(map[int]bool, len(opts.SubjectTypes))
+	for _, t := range opts.SubjectTypes {
+		types[t] = true
+	}
+	relations := make(map[string]bool, len(opts.Relations))
+	for _, r := range opts.Relations {
+		relations[r] = true
+	}
+
+	g := &CollaborationGraph{
+		PersonID: personID,
+		Persons:  make(map[int]*CollaborationPerson),
+		Subjects: make(map[int]string),
+		Errors:   make(map[int]error),
+	}
+	var subjectIDs []int
+	for _, s := range works {
+		if len(types) != 0 && !types[s.Type] {
+			continue
+		}
+		if _, ok := g.Subjects[s.ID]; !ok {
+			subjectIDs = append(subjectIDs, s.ID)
+		}
+		name := s.NameCN
+		if len(name) == 0 {
+			name = s.Name
+		}
+		g.Subjects[s.ID] = name
+	}
+
+	// 每个条目中的人物
+	candidates := make(map[int]*CollaborationPerson)
+	members := make(map[int][]int)
+	results := bulkFetch(ctx, subjectIDs, &BulkOptions{Concurrency: opts.Concurrency, Client: client}, SearchSubjectsPersonsById)
+	for _, subjectID := range subjectIDs {
+		result := results[subjectID]
+		if result.Err != nil {
+			g.Errors[subjectID] = result.Err
+			continue
+		}
+		var persons []RelatedPerson
+		if err := json.Unmarshal(result.Data, &persons); err != nil {
+			g.Errors[subjectID] = errors.New("BuildCollaborationGraph：解析条目人物失败")
+			continue
+		}
+		seen := make(map[int]bool)
+		for _, p := range persons {
+			if p.ID != personID && len(relations) != 0 && !relations[p.Relation] {
+				continue
+			}
+			c := candidates[p.ID]
+			if c == nil {
+				c = &CollaborationPerson{ID: p.ID, Name: p.Name, Career: p.Career, Relations: make(map[string]int)}
+				candidates[p.ID] = c
+			}
+			c.Relations[p.Relation]++
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				c.Shared++
+				members[subjectID] = append(members[subjectID], p.ID)
+			}
+		}
+	}
+
+	// 选择合作者
+	var collaborators []*CollaborationPerson
+	for id, c := range candidates {
+		if id != personID && c.Shared >= minShared {
+			collaborators = append(collaborators, c)
+		}
+	}
+	sort.Slice(collaborators, func(i, j int) bool {
+		if collaborators[i].Shared != collaborators[j].Shared {
+			return collaborators[i].Shared > collaborators[j].Shared
+		}
+		return collaborators[i].ID < collaborators[j].ID
+	})
+	if opts.MaxPersons > 0 && len(collaborators) > opts.MaxPersons {
+		collaborators = collaborators[:opts.MaxPersons]
+	}
+	for _, c := range collaborators {
+		g.Persons[c.ID] = c
+	}
+	center := candidates[personID]
+	if center == nil {
+		center = &CollaborationPerson{ID: personID, Relations: make(map[string]int)}
+	}
+	g.Persons[personID] = center
+
+	// 统计人物两两之间的合作
+	edges := make(map[[2]int]*CollaborationEdge)
+	for _, subjectID := range subjectIDs {
+		var ids []int
+		for _, id := range members[subjectID] {
+			if g.Persons[id] != nil {
+				ids = append(ids, id)
+			}
+		}
+		sort.Ints(ids)
+		for i := 0; i < len(ids); i++ {
+			for j := i + 1; j < len(ids); j++ {
+				key := [2]int{ids[i], ids[j]}
+				e := edges[key]
+				if e == nil {
+					e = &CollaborationEdge{From: ids[i], To: ids[j]}
+					edges[key] = e
+				}
+				e.Weight++
+				e.Subjects = append(e.Subjects, subjectID)
+			}
+		}
+	}
+	for _, e := range edges {
+		g.Edges = append(g.Edges, *e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return g, nil
+}
+
+/*
+TopCollaborators
+
+  - @brief 获取最常合作的人物，按共同参与的条目数从多到少排序。
+
+  - @param
+
+    【n】：最多返回的人数，小于等于0时返回全部。
+
+  - @return 返回一个[]CollaborationPerson，不包括中心人物。
+*/
+func (g *CollaborationGraph) TopCollaborators(n int) []CollaborationPerson {
+	persons := make([]CollaborationPerson, 0, len(g.Persons))
+	for id, p := range g.Persons {
+		if id != g.PersonID {
+			persons = append(persons, *p)
+		}
+	}
+	sort.Slice(persons, func(i, j int) bool {
+		if persons[i].Shared != persons[j].Shared {
+			return persons[i].Shared > persons[j].Shared
+		}
+		return persons[i].ID < persons[j].ID
+	})
+	if n > 0 && len(persons) > n {
+		persons = persons[:n]
+	}
+	return persons
+}
+
+/*
+ * @brief 按ID排序的人物
+ */
+func (g *CollaborationGraph) sortedPersons() []*CollaborationPerson {
+	persons := make([]*CollaborationPerson, 0, len(g.Persons))
+	for _, p := range g.Persons {
+		persons = append(persons, p)
+	}
+	sort.Slice(persons, func(i, j int) bool { return persons[i].ID < persons[j].ID })
+	return persons
+}
+
+/*
+WriteJSON
+
+  - @brief 将关系图写出为JSON，结构为{"person_id": ..., "nodes": [...], "edges": [...], "subjects": {...}}。
+
+  - @param
+
+    【w】：io.Writer对象。
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func (g *CollaborationGraph) WriteJSON(w io.Writer) error {
+	subjects := make(map[string]string, len(g.Subjects))
+	for id, name := range g.Subjects {
+		subjects[strconv.Itoa(id)] = name
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		PersonID int                    `json:"person_id"`
+		Nodes    []*CollaborationPerson `json:"nodes"`
+		Edges    []CollaborationEdge    `json:"edges"`
+		Subjects map[string]string      `json:"subjects"`
+	}{g.PersonID, g.sortedPersons(), g.Edges, subjects})
+}
+
+/*
+WriteDOT
+
+  - @brief 将关系图写出为Graphviz的DOT格式，边的标签为共同参与的条目数，粗细最大为10。
+
+  - @param
+
+    【w】：io.Writer对象。
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func (g *CollaborationGraph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	quote := func(s string) string {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+		s = strings.ReplaceAll(s, `"`, `\"`)
+		s = strings.ReplaceAll(s, "\n", `\n`)
+		return `"` + s + `"`
+	}
+
+	fmt.Fprintf(bw, "graph collaboration_%d {\n", g.PersonID)
+	bw.WriteString("  node [shape=box];\n")
+	for _, p := range g.sortedPersons() {
+		name := p.Name
+		if len(name) == 0 {
+			name = strconv.Itoa(p.ID)
+		}
+		attrs := "label=" + quote(name)
+		if p.ID == g.PersonID {
+			attrs += ", style=bold"
+		}
+		fmt.Fprintf(bw, "  %d [%s];\n", p.ID, attrs)
+	}
+	for _, e := range g.Edges {
+		penwidth := e.Weight
+		if penwidth > 10 {
+			penwidth = 10
+		}
+		fmt.Fprintf(bw, "  %d -- %d [weight=%d, penwidth=%d, label=\"%d\"];\n", e.From, e.To, e.Weight, penwidth, e.Weight)
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
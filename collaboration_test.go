@@ -0,0 +1,141 @@
+package lite_bangumi_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/*
+ * @brief 测试用的人物1参与的条目：10、11为动漫，12为书籍，13获取失败，10重复出现（担任两个职位）
+ */
+func collaborationAPI(t *testing.T) *mockAPI {
+	persons := map[int]string{
+		10: `[{"id":1,"name":"A","relation":"导演"},{"id":2,"name":"B","relation":"原画"},{"id":2,"name":"B","relation":"作画监督"},{"id":3,"name":"C","relation":"音乐"}]`,
+		11: `[{"id":1,"name":"A","relation":"导演"},{"id":2,"name":"B","relation":"原画"},{"id":4,"name":"D \"x\"","relation":"脚本"}]`,
+		12: `[{"id":1,"name":"A","relation":"原作"},{"id":3,"name":"C","relation":"音乐"}]`,
+	}
+	return newMockAPI(t, func(req *http.Request) (int, string) {
+		if req.URL.Path == "/v0/persons/1/subjects" {
+			return http.StatusOK, `[{"id":10,"type":2,"staff":"导演","name":"S10"},{"id":10,"type":2,"staff":"分镜","name":"S10"},
+				{"id":11,"type":2,"name":"S11","name_cn":"十一"},{"id":12,"type":1,"name":"B12"},{"id":13,"type":2,"name":"S13"}]`
+		}
+		var id int
+		if _, err := fmt.Sscanf(req.URL.Path, "/v0/subjects/%d/persons", &id); err == nil && persons[id] != "" {
+			return http.StatusOK, persons[id]
+		}
+		return http.StatusNotFound, `{}`
+	})
+}
+
+func collaborationEdges(g *CollaborationGraph) []string {
+	var edges []string
+	for _, e := range g.Edges {
+		edges = append(edges, fmt.Sprintf("%d-%d:%d%v", e.From, e.To, e.Weight, e.Subjects))
+	}
+	return edges
+}
+
+func TestBuildCollaborationGraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      *CollaborationOptions
+		wantTop   []int
+		wantEdges []string
+	}{
+		{"all subjects", nil, []int{2, 3, 4},
+			[]string{"1-2:2[10 11]", "1-3:2[10 12]", "1-4:1[11]", "2-3:1[10]", "2-4:1[11]"}},
+		{"anime only", &CollaborationOptions{SubjectTypes: []int{2}}, []int{2, 3, 4},
+			[]string{"1-2:2[10 11]", "1-3:1[10]", "1-4:1[11]", "2-3:1[10]", "2-4:1[11]"}},
+		{"min shared", &CollaborationOptions{MinShared: 2}, []int{2, 3},
+			[]string{"1-2:2[10 11]", "1-3:2[10 12]", "2-3:1[10]"}},
+		{"relations", &CollaborationOptions{Relations: []string{"原画"}}, []int{2},
+			[]string{"1-2:2[10 11]"}},
+		{"max persons", &CollaborationOptions{MaxPersons: 1, Concurrency: 1}, []int{2},
+			[]string{"1-2:2[10 11]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := collaborationAPI(t)
+			g, err := BuildCollaborationGraph(context.Background(), 1, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var top []int
+			for _, p := range g.TopCollaborators(0) {
+				top = append(top, p.ID)
+			}
+			if !reflect.DeepEqual(top, tt.wantTop) {
+				t.Errorf("TopCollaborators = %v, want %v", top, tt.wantTop)
+			}
+			if got := collaborationEdges(g); !reflect.DeepEqual(got, tt.wantEdges) {
+				t.Errorf("edges = %v, want %v", got, tt.wantEdges)
+			}
+			if n := api.count("GET /v0/subjects/10/persons"); n != 1 {
+				t.Errorf("duplicated subject requested %d times, want 1", n)
+			}
+		})
+	}
+}
+
+func TestCollaborationGraphDetails(t *testing.T) {
+	collaborationAPI(t)
+	g, err := BuildCollaborationGraph(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Errors[13]; !ok || len(g.Errors) != 1 {
+		t.Errorf("Errors = %v, want subject 13", g.Errors)
+	}
+	if g.Persons[1].Shared != 3 {
+		t.Errorf("center shared = %d, want 3", g.Persons[1].Shared)
+	}
+	if want := map[string]int{"原画": 2, "作画监督": 1}; !reflect.DeepEqual(g.Persons[2].Relations, want) {
+		t.Errorf("relations of 2 = %v, want %v", g.Persons[2].Relations, want)
+	}
+	if g.Subjects[11] != "十一" || g.Subjects[12] != "B12" {
+		t.Errorf("Subjects = %v", g.Subjects)
+	}
+	if top := g.TopCollaborators(1); len(top) != 1 || top[0].ID != 2 {
+		t.Errorf("TopCollaborators(1) = %+v", top)
+	}
+
+	var dot bytes.Buffer
+	if err = g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"graph collaboration_1 {\n", `  1 [label="A", style=bold];`, `  4 [label="D \"x\""];`, `  1 -- 2 [weight=2, penwidth=2, label="2"];`} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT does not contain %q:\n%s", want, dot.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err = g.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		PersonID int                   `json:"person_id"`
+		Nodes    []CollaborationPerson `json:"nodes"`
+		Edges    []CollaborationEdge   `json:"edges"`
+		Subjects map[string]string     `json:"subjects"`
+	}
+	if err = json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.PersonID != 1 || len(decoded.Nodes) != 4 || decoded.Nodes[0].ID != 1 || len(decoded.Edges) != 5 || decoded.Subjects["10"] != "S10" {
+		t.Errorf("JSON = %s", out.String())
+	}
+}
+
+func TestBuildCollaborationGraphPersonNotFound(t *testing.T) {
+	newMockAPI(t, func(req *http.Request) (int, string) { return http.StatusNotFound, `{}` })
+	if _, err := BuildCollaborationGraph(context.Background(), 99, nil); err == nil {
+		t.Error("err = nil, want an error")
+	}
+}
@@ -10,20 +10,94 @@ package lite_bangumi_api
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
+	"time"
 )
 
 /*
- * @brief 定义Token和UserAgent
+ * @brief 定义Token、UserAgent和TokenProvider
+ *
+ * 如果设置了TokenProvider，每次请求时都会从TokenProvider获取token，此时Token将被忽略。
+ * 获取到的token为空时，请求中不会携带Authorization头（匿名访问）。
  */
 var (
-	Token     string
-	UserAgent string
+	Token         string
+	UserAgent     string
+	TokenProvider TokenSource
 )
 
+/*
+ * @brief 一次API调用的信息
+ */
+type requestInfo struct {
+	op     string
+	method string
+	url    string
+	start  time.Time
+	status int
+}
+
+func newRequestInfo(op, method, url string) *requestInfo {
+	return &requestInfo{
+		op:     op,
+		method: method,
+		url:    url,
+		start:  time.Now(),
+	}
+}
+
+/*
+finish
+
+  - @brief API调用结束时记录日志和统计信息
+
+  - @param
+
+    【size】：响应体大小
+
+    【err】：API调用的错误
+*/
+func (info *requestInfo) finish(size int, err error) {
+	recordMetrics(info, err)
+	logRequest(info, size, err)
+}
+
+/*
+setRequestHeader
+
+  - @brief 设置请求头，token从TokenProvider或Token中获取
+
+  - @param
+
+    【req】：http.Request对象
+
+  - @return 返回一个err。
+
+  - @retval 如果err为nil，则没有错误。
+*/
+func setRequestHeader(req *http.Request) error {
+	token := Token
+	if TokenProvider != nil {
+		t, err := TokenProvider.Token()
+		if err != nil {
+			return err
+		}
+		token = t
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if len(token) != 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	return nil
+}
+
 /*
 getJsonDataFromURL
 
@@ -31,48 +105,54 @@ getJsonDataFromURL
 
   - @param
 
+    【op】：操作名，即调用本函数的API函数名，如SearchSubjectsById
+
     【method】：方法
 
     【url】：地址
 
     【requestBody】：请求体
 
-    【client】：http.Client对象
+    【client】：http.Client对象，绑定了context时请求使用该context（见clientWithContext）
 
   - @return 返回一个[]byte和一个err。
 
   - @retval []byte是返回体，err表示错误。如果err为nil，则没有错误。
 */
-func getJsonDataFromURL(method, url, requestBody string, client *http.Client) ([]byte, error) {
+func getJsonDataFromURL(op, method, url, requestBody string, client *http.Client) (body []byte, err error) {
+	info := newRequestInfo(op, method, url)
+	defer func() { info.finish(len(body), err) }()
+
 	var req *http.Request
-	var err error
 	if len(requestBody) == 0 {
-		req, err = http.NewRequest(method, url, nil)
+		req, err = http.NewRequestWithContext(requestContext(client), method, url, nil)
 	} else {
-		req, err = http.NewRequest(method, url, bytes.NewBuffer([]byte(requestBody)))
+		req, err = http.NewRequestWithContext(requestContext(client), method, url, bytes.NewBuffer([]byte(requestBody)))
 	}
 	if err != nil {
 		errMsg := errors.New("getJsonDataFromURL：不正确的请求")
 		return nil, errMsg
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+Token)
-	req.Header.Set("User-Agent", UserAgent)
+	if err = setRequestHeader(req); err != nil {
+		errMsg := errors.New("getJsonDataFromURL：获取token失败")
+		return nil, errMsg
+	}
 
-	resp, err := client.Do(req)
+	resp, err := doRequest(info, req, client)
 	if err != nil {
 		errMsg := errors.New("getJsonDataFromURL：连接失败或超时")
 		return nil, errMsg
 	}
 	defer resp.Body.Close()
+	info.status = resp.StatusCode
 
 	if resp.StatusCode != http.StatusOK {
 		errMsg := errors.New("getJsonDataFromURL：错误的返回码:" + strconv.Itoa(resp.StatusCode))
 		return nil, errMsg
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
 		errMsg := errors.New("getJsonDataFromURL：获取信息失败")
 		return nil, errMsg
@@ -86,41 +166,49 @@ getBoolDataFromURL
 
   - @brief 从URL获取Bool数据
 
-  - @param 【method】：方法
+  - @param
+
+    【op】：操作名，即调用本函数的API函数名，如SearchSubjectsById
+
+    【method】：方法
 
     【url】：地址
 
     【requestBody】：请求体
 
-    【client】：http.Client对象
+    【client】：http.Client对象，绑定了context时请求使用该context（见clientWithContext）
 
   - @return 返回一个bool和一个err。
 
   - @retval  如果bool为true，err为nil。如果bool为false，err表示错误信息
 */
-func getBoolDataFromURL(method, url, requestBody string, client *http.Client) error {
+func getBoolDataFromURL(op, method, url, requestBody string, client *http.Client) (err error) {
+	info := newRequestInfo(op, method, url)
+	defer func() { info.finish(0, err) }()
+
 	var req *http.Request
-	var err error
 	if len(requestBody) == 0 {
-		req, err = http.NewRequest(method, url, nil)
+		req, err = http.NewRequestWithContext(requestContext(client), method, url, nil)
 	} else {
-		req, err = http.NewRequest(method, url, bytes.NewBuffer([]byte(requestBody)))
+		req, err = http.NewRequestWithContext(requestContext(client), method, url, bytes.NewBuffer([]byte(requestBody)))
 	}
 	if err != nil {
 		errMsg := errors.New("getBoolDataFromURL：不正确的请求")
 		return errMsg
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+Token)
-	req.Header.Set("User-Agent", UserAgent)
+	if err = setRequestHeader(req); err != nil {
+		errMsg := errors.New("getBoolDataFromURL：获取token失败")
+		return errMsg
+	}
 
-	resp, err := client.Do(req)
+	resp, err := doRequest(info, req, client)
 	if err != nil {
 		errMsg := errors.New("getBoolDataFromURL：连接失败或超时")
 		return errMsg
 	}
 	defer resp.Body.Close()
+	info.status = resp.StatusCode
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		errMsg := errors.New("getBoolDataFromURL：错误的返回码:" + strconv.Itoa(resp.StatusCode))
@@ -129,3 +217,52 @@ func getBoolDataFromURL(method, url, requestBody string, client *http.Client) er
 
 	return nil
 }
+
+/*
+readJSONFile
+
+  - @brief 读取JSON文件，用于进度、状态等本地文件
+
+  - @param
+
+    【path】：文件路径
+
+    【v】：解析结果
+
+  - @return 返回文件是否存在和一个err。文件不存在时v不变，err为nil。
+*/
+func readJSONFile(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+/*
+writeJSONFile
+
+  - @brief 写入JSON文件。先写入临时文件再重命名，中断时不会留下不完整的文件
+
+  - @param
+
+    【path】：文件路径
+
+    【v】：写入的内容
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
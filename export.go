@@ -0,0 +1,280 @@
+/**
+ * @file 	export.go
+ * @brief 	导出用户全部收藏为JSON、CSV和MyAnimeList XML
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+CollectionExport
+
+  - @brief 用户全部条目收藏的导出结果。
+
+    【UserName】：用户名。
+
+    【ExportedAt】：导出时间。
+
+    【Collections】：全部收藏，包括所有条目类型和所有收藏类型。
+*/
+type CollectionExport struct {
+	UserName    string                  `json:"user_name"`
+	ExportedAt  time.Time               `json:"exported_at"`
+	Collections []UserSubjectCollection `json:"collections"`
+}
+
+/*
+ExportCollections
+
+  - @brief 获取用户全部条目收藏（所有条目类型、所有收藏类型），包括评分、吐槽、标签和进度。
+    查看私有收藏需要 access token。
+
+    API：/v0/users/{username}/collections
+
+  - @param
+
+    【userName】：用户名。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*CollectionExport和一个err。
+
+  - @retval *CollectionExport是导出结果，err表示错误。如果err为nil，则没有错误。
+*/
+func ExportCollections(userName string, client *http.Client) (*CollectionExport, error) {
+	export := &CollectionExport{
+		UserName:   userName,
+		ExportedAt: time.Now(),
+	}
+	for _, subjectTypeName := range SubjectTypeNames {
+		for _, typeName := range CollectionTypeNames {
+			collections, err := GetAllCollectionsByUserName(userName, subjectTypeName, typeName, client)
+			if err != nil {
+				return nil, err
+			}
+			export.Collections = append(export.Collections, collections...)
+		}
+	}
+	return export, nil
+}
+
+/*
+WriteJSON
+
+  - @brief 以JSON格式写出导出结果。
+
+  - @param
+
+    【w】：io.Writer对象。
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func (e *CollectionExport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(e)
+}
+
+/*
+ * @brief CSV的表头
+ */
+var exportCSVHeader = []string{
+	"subject_id", "subject_type", "name", "name_cn", "type", "rate",
+	"ep_status", "vol_status", "eps", "volumes", "comment", "tags", "private", "updated_at",
+}
+
+/*
+WriteCSV
+
+  - @brief 以CSV格式写出导出结果，每个收藏一行。标签以空格分隔，条目类型和收藏类型使用类型名。
+
+  - @param
+
+    【w】：io.Writer对象。
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func (e *CollectionExport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	for _, c := range e.Collections {
+		record := []string{
+			strconv.Itoa(c.SubjectID),
+			SubjectTypeName(c.SubjectType),
+			c.Subject.Name,
+			c.Subject.NameCN,
+			CollectionTypeName(c.Type),
+			strconv.Itoa(c.Rate),
+			strconv.Itoa(c.EpStatus),
+			strconv.Itoa(c.VolStatus),
+			strconv.Itoa(c.Subject.Eps),
+			strconv.Itoa(c.Subject.Volumes),
+			c.Comment,
+			strings.Join(c.Tags, " "),
+			strconv.FormatBool(c.Private),
+			c.UpdatedAt.Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+/*
+ * @brief MyAnimeList导出格式
+ */
+type malExport struct {
+	XMLName xml.Name   `xml:"myanimelist"`
+	MyInfo  malMyInfo  `xml:"myinfo"`
+	Anime   []malAnime `xml:"anime"`
+	Manga   []malManga `xml:"manga"`
+}
+
+type malMyInfo struct {
+	UserName       string `xml:"user_name"`
+	UserExportType int    `xml:"user_export_type"`
+}
+
+type malAnime struct {
+	SeriesAnimeDBID   int    `xml:"series_animedb_id"`
+	SeriesTitle       string `xml:"series_title"`
+	SeriesEpisodes    int    `xml:"series_episodes"`
+	MyWatchedEpisodes int    `xml:"my_watched_episodes"`
+	MyStartDate       string `xml:"my_start_date"`
+	MyFinishDate      string `xml:"my_finish_date"`
+	MyScore           int    `xml:"my_score"`
+	MyStatus          string `xml:"my_status"`
+	MyComments        string `xml:"my_comments"`
+	MyTags            string `xml:"my_tags"`
+	UpdateOnImport    int    `xml:"update_on_import"`
+}
+
+type malManga struct {
+	MangaDBID      int    `xml:"manga_mangadb_id"`
+	MangaTitle     string `xml:"manga_title"`
+	MangaVolumes   int    `xml:"manga_volumes"`
+	MangaChapters  int    `xml:"manga_chapters"`
+	MyReadVolumes  int    `xml:"my_read_volumes"`
+	MyReadChapters int    `xml:"my_read_chapters"`
+	MyStartDate    string `xml:"my_start_date"`
+	MyFinishDate   string `xml:"my_finish_date"`
+	MyScore        int    `xml:"my_score"`
+	MyStatus       string `xml:"my_status"`
+	MyComments     string `xml:"my_comments"`
+	MyTags         string `xml:"my_tags"`
+	UpdateOnImport int    `xml:"update_on_import"`
+}
+
+/*
+malStatus
+
+  - @brief 收藏类型转为MyAnimeList的状态
+
+  - @param
+
+    【collectionType】：收藏类型ID
+
+    【planStatus】：想看对应的状态（Plan to Watch或Plan to Read）
+
+    【currentStatus】：在看对应的状态（Watching或Reading）
+
+  - @return 返回MyAnimeList的状态。
+*/
+func malStatus(collectionType int, planStatus, currentStatus string) string {
+	switch collectionType {
+	case 1:
+		return planStatus
+	case 2:
+		return "Completed"
+	case 3:
+		return currentStatus
+	case 4:
+		return "On-Hold"
+	case 5:
+		return "Dropped"
+	default:
+		return planStatus
+	}
+}
+
+/*
+WriteMALXML
+
+  - @brief 以MyAnimeList导入格式写出导出结果。动漫条目输出为anime，书籍条目输出为manga，其他类型的条目会被忽略。
+    MyAnimeList的ID未知，统一为0，MyAnimeList导入时会按标题匹配。
+
+  - @param
+
+    【w】：io.Writer对象。
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func (e *CollectionExport) WriteMALXML(w io.Writer) error {
+	export := malExport{
+		MyInfo: malMyInfo{UserName: e.UserName, UserExportType: 1},
+	}
+	for _, c := range e.Collections {
+		finishDate := "0000-00-00"
+		if c.Type == 2 && !c.UpdatedAt.IsZero() {
+			finishDate = c.UpdatedAt.Format("2006-01-02")
+		}
+		switch c.SubjectType {
+		case 2:
+			export.Anime = append(export.Anime, malAnime{
+				SeriesTitle:       c.Subject.Name,
+				SeriesEpisodes:    c.Subject.Eps,
+				MyWatchedEpisodes: c.EpStatus,
+				MyStartDate:       "0000-00-00",
+				MyFinishDate:      finishDate,
+				MyScore:           c.Rate,
+				MyStatus:          malStatus(c.Type, "Plan to Watch", "Watching"),
+				MyComments:        c.Comment,
+				MyTags:            strings.Join(c.Tags, ","),
+				UpdateOnImport:    1,
+			})
+		case 1:
+			export.Manga = append(export.Manga, malManga{
+				MangaTitle:     c.Subject.Name,
+				MangaVolumes:   c.Subject.Volumes,
+				MangaChapters:  c.Subject.Eps,
+				MyReadVolumes:  c.VolStatus,
+				MyReadChapters: c.EpStatus,
+				MyStartDate:    "0000-00-00",
+				MyFinishDate:   finishDate,
+				MyScore:        c.Rate,
+				MyStatus:       malStatus(c.Type, "Plan to Read", "Reading"),
+				MyComments:     c.Comment,
+				MyTags:         strings.Join(c.Tags, ","),
+				UpdateOnImport: 1,
+			})
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "\t")
+	if err := encoder.Encode(export); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,421 @@
+/**
+ * @file 	franchise.go
+ * @brief 	沿条目关系遍历系列作品，生成关系图和观看顺序
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+ * @brief 默认遍历的关系
+ */
+var DefaultFranchiseRelations = []string{"前传", "续集", "番外篇", "总集篇", "全集", "主线故事"}
+
+/*
+ * @brief 默认最大深度
+ */
+const defaultFranchiseDepth = 3
+
+/*
+SubjectCache
+
+  - @brief 条目和相关条目的缓存，可以在多次遍历之间共用以减少请求。可以并发使用。
+*/
+type SubjectCache struct {
+	mu        sync.Mutex
+	subjects  map[int]*Subject
+	relations map[int][]RelatedSubject
+}
+
+/*
+NewSubjectCache
+
+  - @brief 创建一个空的SubjectCache。
+
+  - @return 返回一个*SubjectCache。
+*/
+func NewSubjectCache() *SubjectCache {
+	return &SubjectCache{
+		subjects:  make(map[int]*Subject),
+		relations: make(map[int][]RelatedSubject),
+	}
+}
+
+/*
+Subject
+
+  - @brief 获取缓存中的条目。
+
+  - @param
+
+    【id】：条目ID。
+
+  - @return 返回一个*Subject和是否存在。
+*/
+func (c *SubjectCache) Subject(id int) (*Subject, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.subjects[id]
+	return s, ok
+}
+
+/*
+Relations
+
+  - @brief 获取缓存中的相关条目。
+
+  - @param
+
+    【id】：条目ID。
+
+  - @return 返回一个[]RelatedSubject和是否存在。
+*/
+func (c *SubjectCache) Relations(id int) ([]RelatedSubject, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.relations[id]
+	return r, ok
+}
+
+/*
+loadSubjects
+
+  - @brief 获取多个条目，缓存中没有的条目并发获取后写入缓存
+
+  - @param
+
+    【ctx】：context
+
+    【ids】：条目ID列表
+
+    【opts】：批量获取的选项
+
+  - @return 返回以条目ID为键的条目和错误。
+*/
+func (c *SubjectCache) loadSubjects(ctx context.Context, ids []int, opts *BulkOptions) (map[int]*Subject, map[int]error) {
+	subjects := make(map[int]*Subject, len(ids))
+	errs := make(map[int]error)
+	var missing []int
+	for _, id := range ids {
+		if s, ok := c.Subject(id); ok {
+			subjects[id] = s
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	for id, result := range bulkFetch(ctx, missing, opts, SearchSubjectsById) {
+		if result.Err != nil {
+			errs[id] = result.Err
+			continue
+		}
+		var s Subject
+		if err := json.Unmarshal(result.Data, &s); err != nil {
+			errs[id] = errors.New("TraverseFranchise：解析条目失败")
+			continue
+		}
+		c.mu.Lock()
+		c.subjects[id] = &s
+		c.mu.Unlock()
+		subjects[id] = &s
+	}
+	return subjects, errs
+}
+
+/*
+loadRelations
+
+  - @brief 获取多个条目的相关条目，缓存中没有的并发获取后写入缓存
+
+  - @param
+
+    【ctx】：context
+
+    【ids】：条目ID列表
+
+    【opts】：批量获取的选项
+
+  - @return 返回以条目ID为键的相关条目和错误。
+*/
+func (c *SubjectCache) loadRelations(ctx context.Context, ids []int, opts *BulkOptions) (map[int][]RelatedSubject, map[int]error) {
+	relations := make(map[int][]RelatedSubject, len(ids))
+	errs := make(map[int]error)
+	var missing []int
+	for _, id := range ids {
+		if r, ok := c.Relations(id); ok {
+			relations[id] = r
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	for id, result := range bulkFetch(ctx, missing, opts, SearchSubjectsRelationsById) {
+		if result.Err != nil {
+			errs[id] = result.Err
+			continue
+		}
+		var r []RelatedSubject
+		if err := json.Unmarshal(result.Data, &r); err != nil {
+			errs[id] = errors.New("TraverseFranchise：解析相关条目失败")
+			continue
+		}
+		c.mu.Lock()
+		c.relations[id] = r
+		c.mu.Unlock()
+		relations[id] = r
+	}
+	return relations, errs
+}
+
+/*
+FranchiseOptions
+
+  - @brief 遍历系列作品的选项。
+
+    【MaxDepth】：最大深度，起始条目为0，小于等于0时为3。最大深度上的条目会被获取，但不会继续遍历它们的关系。
+
+    【Relations】：遍历的关系，为nil时为DefaultFranchiseRelations。
+
+    【Concurrency】：最大并发数，小于等于0时为4。请求仍然会经过RateLimit限速。
+
+    【Cache】：缓存，为nil时只在本次遍历中缓存。
+
+    【Client】：http.Client对象，为nil时使用http.DefaultClient。
+*/
+type FranchiseOptions struct {
+	MaxDepth    int
+	Relations   []string
+	Concurrency int
+	Cache       *SubjectCache
+	Client      *http.Client
+}
+
+/*
+ * @brief 系列作品中的一个条目。Depth为与起始条目的距离
+ */
+type FranchiseSubject struct {
+	ID     int
+	Type   int
+	Name   string
+	NameCN string
+	Date   string
+	Eps    int
+	Depth  int
+}
+
+/*
+ * @brief 两个条目之间的关系，表示To是From的Relation，如To是From的"续集"
+ */
+type FranchiseEdge struct {
+	From     int
+	To       int
+	Relation string
+}
+
+/*
+Franchise
+
+  - @brief 系列作品的关系图。
+
+    【RootID】：起始条目ID。
+
+    【Subjects】：以条目ID为键的条目。
+
+    【Edges】：关系，只包含Subjects中的条目之间的关系。
+
+    【Errors】：获取条目或相关条目失败的条目ID和错误。获取条目失败的条目不在Subjects中。
+*/
+type Franchise struct {
+	RootID   int
+	Subjects map[int]*FranchiseSubject
+	Edges    []FranchiseEdge
+	Errors   map[int]error
+}
+
+/*
+TraverseFranchise
+
+  - @brief 从一个条目开始，沿前传、续集等关系广度优先遍历系列作品。已经访问的条目不会重复访问，因此关系中的环不会导致死循环。
+    每一层的条目并发获取。
+
+    API：/v0/subjects/{subject_id}、/v0/subjects/{subject_id}/subjects
+
+  - @param
+
+    【ctx】：context。
+
+    【subjectID】：起始条目ID。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回一个*Franchise和一个err。
+
+  - @retval 只有起始条目获取失败时返回err，其他条目的错误记录在Franchise.Errors中。
+*/
+func TraverseFranchise(ctx context.Context, subjectID int, opts *FranchiseOptions) (*Franchise, error) {
+	maxDepth := defaultFranchiseDepth
+	relationNames := DefaultFranchiseRelations
+	cache := NewSubjectCache()
+	bulk := &BulkOptions{}
+	if opts != nil {
+		if opts.MaxDepth > 0 {
+			maxDepth = opts.MaxDepth
+		}
+		if opts.Relations != nil {
+			relationNames = opts.Relations
+		}
+		if opts.Cache != nil {
+			cache = opts.Cache
+		}
+		bulk.Concurrency = opts.Concurrency
+		bulk.Client = opts.Client
+	}
+	follow := make(map[string]bool, len(relationNames))
+	for _, name := range relationNames {
+		follow[name] = true
+	}
+
+	f := &Franchise{
+		RootID:   subjectID,
+		Subjects: make(map[int]*FranchiseSubject),
+		Errors:   make(map[int]error),
+	}
+	visited := map[int]bool{subjectID: true}
+	edges := make(map[FranchiseEdge]bool)
+	frontier := []int{subjectID}
+
+	for depth := 0; len(frontier) != 0; depth++ {
+		subjects, errs := cache.loadSubjects(ctx, frontier, bulk)
+		if err, ok := errs[subjectID]; ok && depth == 0 {
+			return nil, err
+		}
+		var expand []int
+		for _, id := range frontier {
+			if err, ok := errs[id]; ok {
+				f.Errors[id] = err
+				continue
+			}
+			s := subjects[id]
+			f.Subjects[id] = &FranchiseSubject{
+				ID:     s.ID,
+				Type:   s.Type,
+				Name:   s.Name,
+				NameCN: s.NameCN,
+				Date:   s.Date,
+				Eps:    s.Eps,
+				Depth:  depth,
+			}
+			expand = append(expand, id)
+		}
+		if depth == maxDepth {
+			break
+		}
+
+		relations, errs := cache.loadRelations(ctx, expand, bulk)
+		var next []int
+		for _, id := range expand {
+			if err, ok := errs[id]; ok {
+				f.Errors[id] = err
+				continue
+			}
+			for _, r := range relations[id] {
+				if !follow[r.Relation] {
+					continue
+				}
+				edge := FranchiseEdge{From: id, To: r.ID, Relation: r.Relation}
+				if !edges[edge] {
+					edges[edge] = true
+					f.Edges = append(f.Edges, edge)
+				}
+				if !visited[r.ID] {
+					visited[r.ID] = true
+					next = append(next, r.ID)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	// 去掉指向未获取的条目的关系
+	kept := f.Edges[:0]
+	for _, edge := range f.Edges {
+		if f.Subjects[edge.From] != nil && f.Subjects[edge.To] != nil {
+			kept = append(kept, edge)
+		}
+	}
+	f.Edges = kept
+	return f, nil
+}
+
+/*
+parseSubjectDate
+
+  - @brief 解析条目日期，支持"2006-01-02"、"2006-01"和"2006"
+
+  - @param
+
+    【date】：日期
+
+  - @return 返回一个time.Time和是否成功。
+*/
+func parseSubjectDate(date string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+/*
+WatchOrder
+
+  - @brief 按放送日期生成推荐的观看顺序。日期相同时按条目ID排序，没有日期的条目排在最后。
+
+  - @param
+
+    【types】：只包含这些类型的条目，为空时只包含与起始条目类型相同的条目。
+
+  - @return 返回一个[]FranchiseSubject。
+*/
+func (f *Franchise) WatchOrder(types ...int) []FranchiseSubject {
+	if len(types) == 0 {
+		if root := f.Subjects[f.RootID]; root != nil {
+			types = []int{root.Type}
+		}
+	}
+	include := make(map[int]bool, len(types))
+	for _, t := range types {
+		include[t] = true
+	}
+
+	order := make([]FranchiseSubject, 0, len(f.Subjects))
+	for _, s := range f.Subjects {
+		if include[s.Type] {
+			order = append(order, *s)
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, aOK := parseSubjectDate(order[i].Date)
+		b, bOK := parseSubjectDate(order[j].Date)
+		switch {
+		case aOK != bOK:
+			return aOK
+		case aOK && !a.Equal(b):
+			return a.Before(b)
+		default:
+			return order[i].ID < order[j].ID
+		}
+	})
+	return order
+}
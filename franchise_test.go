@@ -0,0 +1,156 @@
+package lite_bangumi_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+/*
+ * @brief 测试用的系列作品：1 -续集-> 2 -续集-> 3 -番外篇-> 4，2 -前传-> 1（环），
+ * 1 -主线故事-> 6（书籍），1 -其他-> 9（不遍历），2 -续集-> 5（获取失败）
+ */
+var franchiseSubjects = map[int]string{
+	1: `{"id":1,"type":2,"name":"S1","date":"2011-01-07"}`,
+	2: `{"id":2,"type":2,"name":"S2","date":"2012-10"}`,
+	3: `{"id":3,"type":2,"name":"S3","date":"2012-10-06"}`,
+	4: `{"id":4,"type":2,"name":"OVA"}`,
+	6: `{"id":6,"type":1,"name":"Novel","date":"2010"}`,
+	9: `{"id":9,"type":2,"name":"Other"}`,
+}
+
+var franchiseRelations = map[int]string{
+	1: `[{"id":2,"relation":"续集"},{"id":6,"relation":"主线故事"},{"id":9,"relation":"其他"}]`,
+	2: `[{"id":1,"relation":"前传"},{"id":3,"relation":"续集"},{"id":5,"relation":"续集"}]`,
+	3: `[{"id":4,"relation":"番外篇"}]`,
+	4: `[{"id":3,"relation":"前传"}]`,
+	6: `[]`,
+}
+
+func franchiseAPI(t *testing.T) *mockAPI {
+	return newMockAPI(t, func(req *http.Request) (int, string) {
+		var id int
+		if _, err := fmt.Sscanf(req.URL.Path, "/v0/subjects/%d", &id); err != nil {
+			return http.StatusNotFound, `{}`
+		}
+		body, ok := franchiseSubjects[id]
+		if strings.HasSuffix(req.URL.Path, "/subjects") {
+			body, ok = franchiseRelations[id]
+		}
+		if !ok {
+			return http.StatusNotFound, `{}`
+		}
+		return http.StatusOK, body
+	})
+}
+
+func franchiseIDs(f *Franchise) map[int]int {
+	depths := make(map[int]int, len(f.Subjects))
+	for id, s := range f.Subjects {
+		depths[id] = s.Depth
+	}
+	return depths
+}
+
+func TestTraverseFranchise(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxDepth   int
+		want       map[int]int
+		wantEdges  int
+		wantErrors []int
+	}{
+		{"default depth", 0, map[int]int{1: 0, 2: 1, 6: 1, 3: 2, 4: 3}, 5, []int{5}},
+		{"depth 1", 1, map[int]int{1: 0, 2: 1, 6: 1}, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := franchiseAPI(t)
+			f, err := TraverseFranchise(context.Background(), 1, &FranchiseOptions{MaxDepth: tt.maxDepth, Concurrency: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := franchiseIDs(f); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subjects = %v, want %v", got, tt.want)
+			}
+			// 只保留已获取的条目之间的关系
+			for _, edge := range f.Edges {
+				if f.Subjects[edge.From] == nil || f.Subjects[edge.To] == nil {
+					t.Errorf("edge %+v points outside the franchise", edge)
+				}
+			}
+			if len(f.Edges) != tt.wantEdges {
+				t.Errorf("edges = %+v, want %d", f.Edges, tt.wantEdges)
+			}
+			var errIDs []int
+			for id := range f.Errors {
+				errIDs = append(errIDs, id)
+			}
+			sort.Ints(errIDs)
+			if !reflect.DeepEqual(errIDs, tt.wantErrors) {
+				t.Errorf("errors = %v, want %v", errIDs, tt.wantErrors)
+			}
+			if n := api.count("GET /v0/subjects/1/subjects"); n != 1 {
+				t.Errorf("relations of the root requested %d times, want 1", n)
+			}
+			if n := api.count("GET /v0/subjects/4/subjects"); n != 0 {
+				t.Errorf("relations at the max depth requested %d times, want 0", n)
+			}
+		})
+	}
+}
+
+func TestTraverseFranchiseCache(t *testing.T) {
+	api := franchiseAPI(t)
+	cache := NewSubjectCache()
+	if _, err := TraverseFranchise(context.Background(), 1, &FranchiseOptions{Cache: cache}); err != nil {
+		t.Fatal(err)
+	}
+	requests := api.count("GET")
+	f, err := TraverseFranchise(context.Background(), 3, &FranchiseOptions{Cache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Subjects) == 0 || f.Subjects[3].Depth != 0 {
+		t.Errorf("subjects = %v, want 3 as the root", franchiseIDs(f))
+	}
+	// 第一次遍历中4在最大深度上，没有获取它的相关条目
+	if n := api.count("GET") - requests; n != 1 {
+		t.Errorf("cached traversal sent %d requests, want 1", n)
+	}
+
+	if _, err = TraverseFranchise(context.Background(), 5, nil); err == nil {
+		t.Error("root not found: err = nil, want an error")
+	}
+}
+
+func TestFranchiseWatchOrder(t *testing.T) {
+	franchiseAPI(t)
+	f, err := TraverseFranchise(context.Background(), 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		types []int
+		want  []int
+	}{
+		{"root type", nil, []int{1, 2, 3, 4}},
+		{"with books", []int{1, 2}, []int{6, 1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, s := range f.WatchOrder(tt.types...) {
+				got = append(got, s.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WatchOrder(%v) = %v, want %v", tt.types, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,3 +1,3 @@
 module lite_bangumi_api
 
-go 1.20
+go 1.21
@@ -0,0 +1,273 @@
+/**
+ * @file 	ics.go
+ * @brief 	将每日放送转为iCalendar（RFC 5545）订阅
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*
+ICSOptions
+
+  - @brief 生成iCalendar的选项。
+
+    【CalendarName】：日历名，为空时为"Bangumi 每日放送"。
+
+    【Location】：日历显示时区，写入X-WR-TIMEZONE，为nil时为Asia/Tokyo。定时事件以UTC时间写入，日历应用会换算到显示时区。
+
+    【AirTimes】：条目的放送时刻（日本时间当天0点起的时长），键为条目ID。
+    有放送时刻的条目生成定时事件，否则生成全天事件（日期为日本时间的放送星期）。
+
+    【Duration】：定时事件的时长，为0时为30分钟。
+
+    【SubjectIDs】：只输出这些条目，为nil时输出全部条目。
+
+    【Now】：生成时间，为零值时使用当前时间。
+*/
+type ICSOptions struct {
+	CalendarName string
+	Location     *time.Location
+	AirTimes     map[int]time.Duration
+	Duration     time.Duration
+	SubjectIDs   []int
+	Now          time.Time
+}
+
+/*
+WriteCalendarICS
+
+  - @brief 将每日放送写出为iCalendar，每个条目一个每周重复的事件。开播日期和话数已知时重复次数为话数，否则一直重复。
+
+  - @param
+
+    【w】：io.Writer对象。
+
+    【days】：每日放送。
+
+    【opts】：选项。
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func WriteCalendarICS(w io.Writer, days []CalendarDay, opts ICSOptions) error {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	name := opts.CalendarName
+	if len(name) == 0 {
+		name = "Bangumi 每日放送"
+	}
+	duration := opts.Duration
+	if duration <= 0 {
+		duration = 30 * time.Minute
+	}
+	var filter map[int]bool
+	if opts.SubjectIDs != nil {
+		filter = make(map[int]bool, len(opts.SubjectIDs))
+		for _, id := range opts.SubjectIDs {
+			filter[id] = true
+		}
+	}
+
+	var b bytes.Buffer
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//lite_bangumi_api//calendar//CN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:"+escapeICSText(name))
+	if opts.Location != nil {
+		writeICSLine(&b, "X-WR-TIMEZONE:"+opts.Location.String())
+	} else {
+		writeICSLine(&b, "X-WR-TIMEZONE:Asia/Tokyo")
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, day := range days {
+		for _, item := range day.Items {
+			if filter != nil && !filter[item.ID] {
+				continue
+			}
+			weekday := item.AirWeekday
+			if weekday == 0 {
+				weekday = day.Weekday.ID
+			}
+			first, known := firstAirDate(item.AirDate, weekday, now)
+			// 开播日期和话数都已知时，重复次数为话数
+			count := item.Eps
+			if count <= 0 {
+				count = item.EpsCount
+			}
+
+			title := item.NameCN
+			if len(title) == 0 {
+				title = item.Name
+			}
+			url := item.URL
+			if len(url) == 0 {
+				url = fmt.Sprintf("https://bgm.tv/subject/%d", item.ID)
+			}
+
+			writeICSLine(&b, "BEGIN:VEVENT")
+			writeICSLine(&b, fmt.Sprintf("UID:bangumi-subject-%d@bgm.tv", item.ID))
+			writeICSLine(&b, "DTSTAMP:"+stamp)
+			if airTime, ok := opts.AirTimes[item.ID]; ok {
+				start := first.Add(airTime).UTC()
+				writeICSLine(&b, "DTSTART:"+start.Format("20060102T150405Z"))
+				writeICSLine(&b, "DTEND:"+start.Add(duration).Format("20060102T150405Z"))
+			} else {
+				writeICSLine(&b, "DTSTART;VALUE=DATE:"+first.Format("20060102"))
+				writeICSLine(&b, "DTEND;VALUE=DATE:"+first.AddDate(0, 0, 1).Format("20060102"))
+			}
+			if known && count > 0 {
+				writeICSLine(&b, fmt.Sprintf("RRULE:FREQ=WEEKLY;COUNT=%d", count))
+			} else {
+				writeICSLine(&b, "RRULE:FREQ=WEEKLY")
+			}
+			writeICSLine(&b, "SUMMARY:"+escapeICSText(title))
+			if len(item.Name) != 0 && item.Name != title {
+				writeICSLine(&b, "DESCRIPTION:"+escapeICSText(item.Name+"\n"+url))
+			} else {
+				writeICSLine(&b, "DESCRIPTION:"+escapeICSText(url))
+			}
+			writeICSLine(&b, "URL:"+url)
+			writeICSLine(&b, "TRANSP:TRANSPARENT")
+			writeICSLine(&b, "END:VEVENT")
+		}
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+/*
+firstAirDate
+
+  - @brief 计算事件的第一次放送日期（日本时间0点）
+
+  - @param
+
+    【airDate】：开播日期，格式为2006-01-02，可以为空
+
+    【weekday】：放送星期，1～7
+
+    【now】：当前时间
+
+  - @return 返回开播日期当天或之后的第一个放送星期，以及开播日期是否已知。开播日期未知时为本周（星期一开始）的放送星期。
+*/
+func firstAirDate(airDate string, weekday int, now time.Time) (time.Time, bool) {
+	base, err := time.ParseInLocation("2006-01-02", airDate, japanLocation)
+	known := err == nil
+	if !known {
+		n := now.In(japanLocation)
+		base = time.Date(n.Year(), n.Month(), n.Day(), 0, 0, 0, 0, japanLocation)
+	}
+	// 以星期一为一周的开始
+	current := int(base.Weekday())
+	if current == 0 {
+		current = 7
+	}
+	if !known {
+		return base.AddDate(0, 0, weekday-current), false
+	}
+	return base.AddDate(0, 0, (weekday-current+7)%7), true
+}
+
+/*
+writeICSLine
+
+  - @brief 写入一行，超过75字节时按RFC 5545折行
+
+  - @param
+
+    【b】：缓冲区
+
+    【line】：内容
+*/
+func writeICSLine(b *bytes.Buffer, line string) {
+	// 续行开头的空格也计入长度
+	maxLen := 75
+	for len(line) > maxLen {
+		cut := maxLen
+		// 不要从UTF-8字符中间截断
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		maxLen = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func escapeICSText(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(text)
+}
+
+/*
+GetCalendarICS
+
+  - @brief 获取每日放送并转为iCalendar。userName不为空时只输出该用户在看的动漫条目。
+
+    API：/calendar、/v0/users/{username}/collections
+
+  - @param
+
+    【userName】：用户名，可以为空。
+
+    【opts】：选项。opts.SubjectIDs不为nil时会与用户在看的条目取交集。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]byte和一个err。
+
+  - @retval []byte是iCalendar内容，err表示错误。如果err为nil，则没有错误。
+*/
+func GetCalendarICS(userName string, opts ICSOptions, client *http.Client) ([]byte, error) {
+	days, err := GetCalendarDays(client)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userName) != 0 {
+		collections, err := GetAllCollectionsByUserName(userName, "动漫", "在看", client)
+		if err != nil {
+			return nil, err
+		}
+		var allowed map[int]bool
+		if opts.SubjectIDs != nil {
+			allowed = make(map[int]bool, len(opts.SubjectIDs))
+			for _, id := range opts.SubjectIDs {
+				allowed[id] = true
+			}
+		}
+		ids := make([]int, 0, len(collections))
+		for _, c := range collections {
+			if allowed == nil || allowed[c.SubjectID] {
+				ids = append(ids, c.SubjectID)
+			}
+		}
+		opts.SubjectIDs = ids
+	}
+
+	var b bytes.Buffer
+	if err = WriteCalendarICS(&b, days, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,152 @@
+package lite_bangumi_api
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:a"},
+		{"exactly 75 bytes", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"long ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"long multibyte", "SUMMARY:" + strings.Repeat("魔法少女まどか☆マギカ", 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			writeICSLine(&b, tt.line)
+			out := b.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q does not end with CRLF", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, l := range lines {
+				if len(l) > 75 {
+					t.Errorf("line %d is %d bytes", i, len(l))
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("line %d splits a UTF-8 character: %q", i, l)
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, l)
+				}
+			}
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolded = %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"a,b;c", `a\,b\;c`},
+		{`C:\dir`, `C:\\dir`},
+		{"a\r\nb\nc", `a\nb\nc`},
+	}
+	for _, tt := range tests {
+		if got := escapeICSText(tt.text); got != tt.want {
+			t.Errorf("escapeICSText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFirstAirDate(t *testing.T) {
+	// 2026-10-15 星期四
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, japanLocation)
+	tests := []struct {
+		name      string
+		airDate   string
+		weekday   int
+		want      string
+		wantKnown bool
+	}{
+		{"premiere on the weekday", "2026-10-01", 4, "2026-10-01", true},
+		{"weekday after the premiere", "2026-10-01", 5, "2026-10-02", true},
+		{"weekday before the premiere moves to next week", "2026-10-01", 3, "2026-10-07", true},
+		{"sunday premiere", "2026-10-04", 7, "2026-10-04", true},
+		{"monday after a sunday premiere", "2026-10-04", 1, "2026-10-05", true},
+		{"unknown premiere uses this week", "", 1, "2026-10-12", false},
+		{"unknown premiere on sunday", "", 7, "2026-10-18", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, known := firstAirDate(tt.airDate, tt.weekday, now)
+			if got.Format("2006-01-02") != tt.want || known != tt.wantKnown {
+				t.Errorf("firstAirDate(%q, %d) = %s %v, want %s %v", tt.airDate, tt.weekday, got.Format("2006-01-02"), known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestWriteCalendarICS(t *testing.T) {
+	days := []CalendarDay{
+		{Weekday: CalendarWeekday{ID: 4}, Items: []CalendarItem{
+			{ID: 1, Name: "Madoka", NameCN: "小圆", AirDate: "2026-10-01", Eps: 12},
+			{ID: 3, Name: "filtered"},
+		}},
+		{Weekday: CalendarWeekday{ID: 7}, Items: []CalendarItem{
+			{ID: 2, Name: "Unknown, premiere", EpsCount: 24},
+		}},
+	}
+	var b bytes.Buffer
+	err := WriteCalendarICS(&b, days, ICSOptions{
+		AirTimes:   map[int]time.Duration{1: 23*time.Hour + 30*time.Minute},
+		SubjectIDs: []int{1, 2},
+		Now:        time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	events := strings.Split(out, "BEGIN:VEVENT\r\n")[1:]
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2:\n%s", len(events), out)
+	}
+	tests := []struct {
+		name  string
+		event string
+		want  []string
+	}{
+		{"timed event with count", events[0], []string{
+			"UID:bangumi-subject-1@bgm.tv\r\n",
+			"DTSTART:20261001T143000Z\r\n",
+			"DTEND:20261001T150000Z\r\n",
+			"RRULE:FREQ=WEEKLY;COUNT=12\r\n",
+			"SUMMARY:小圆\r\n",
+			`DESCRIPTION:Madoka\nhttps://bgm.tv/subject/1` + "\r\n",
+		}},
+		{"all-day event without premiere", events[1], []string{
+			"DTSTART;VALUE=DATE:20261018\r\n",
+			"DTEND;VALUE=DATE:20261019\r\n",
+			"RRULE:FREQ=WEEKLY\r\n",
+			`SUMMARY:Unknown\, premiere` + "\r\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.want {
+				if !strings.Contains(tt.event, want) {
+					t.Errorf("event does not contain %q:\n%s", want, tt.event)
+				}
+			}
+		})
+	}
+	if strings.Contains(out, "bangumi-subject-3") {
+		t.Error("filtered subject was written")
+	}
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Error("calendar is not wrapped in VCALENDAR")
+	}
+}
@@ -0,0 +1,480 @@
+/**
+ * @file 	import.go
+ * @brief 	从导出文件恢复用户收藏
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+LoadCollectionExportJSON
+
+  - @brief 读取WriteJSON写出的导出文件。
+
+  - @param
+
+    【r】：io.Reader对象。
+
+  - @return 返回一个*CollectionExport和一个err。
+
+  - @retval *CollectionExport是导出结果，err表示错误。如果err为nil，则没有错误。
+*/
+func LoadCollectionExportJSON(r io.Reader) (*CollectionExport, error) {
+	var export CollectionExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		errMsg := errors.New("LoadCollectionExportJSON：解析导出文件失败")
+		return nil, errMsg
+	}
+	return &export, nil
+}
+
+/*
+LoadCollectionExportCSV
+
+  - @brief 读取WriteCSV写出的导出文件。CSV中没有用户名，返回结果的UserName为空。
+
+  - @param
+
+    【r】：io.Reader对象。
+
+  - @return 返回一个*CollectionExport和一个err。
+
+  - @retval *CollectionExport是导出结果，err表示错误。如果err为nil，则没有错误。
+*/
+func LoadCollectionExportCSV(r io.Reader) (*CollectionExport, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil || len(records) == 0 {
+		errMsg := errors.New("LoadCollectionExportCSV：解析导出文件失败")
+		return nil, errMsg
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	for _, name := range exportCSVHeader {
+		if _, ok := columns[name]; !ok {
+			errMsg := errors.New("LoadCollectionExportCSV：缺少列:" + name)
+			return nil, errMsg
+		}
+	}
+
+	export := &CollectionExport{}
+	for _, record := range records[1:] {
+		field := func(name string) string {
+			return record[columns[name]]
+		}
+		number := func(name string) int {
+			n, _ := strconv.Atoi(field(name))
+			return n
+		}
+		c := UserSubjectCollection{
+			SubjectID:   number("subject_id"),
+			SubjectType: SubjectTypeID(field("subject_type")),
+			Type:        CollectionTypeID(field("type")),
+			Rate:        number("rate"),
+			EpStatus:    number("ep_status"),
+			VolStatus:   number("vol_status"),
+			Comment:     field("comment"),
+			Tags:        strings.Fields(field("tags")),
+		}
+		c.Private, _ = strconv.ParseBool(field("private"))
+		c.UpdatedAt, _ = time.Parse(time.RFC3339, field("updated_at"))
+		c.Subject = SlimSubject{
+			ID:      c.SubjectID,
+			Type:    c.SubjectType,
+			Name:    field("name"),
+			NameCN:  field("name_cn"),
+			Eps:     number("eps"),
+			Volumes: number("volumes"),
+		}
+		if c.SubjectID == 0 {
+			errMsg := errors.New("LoadCollectionExportCSV：错误的subject_id:" + field("subject_id"))
+			return nil, errMsg
+		}
+		export.Collections = append(export.Collections, c)
+	}
+	return export, nil
+}
+
+/*
+ConflictPolicy
+
+  - @brief 文件中的收藏与服务器上的收藏不同时的处理方式。
+*/
+type ConflictPolicy int
+
+const (
+	// ConflictServerWins 保留服务器上的收藏
+	ConflictServerWins ConflictPolicy = iota
+	// ConflictFileWins 使用文件中的收藏覆盖服务器
+	ConflictFileWins
+	// ConflictNewestWins 使用updated_at较新的一方
+	ConflictNewestWins
+)
+
+/*
+ImportAction
+
+  - @brief 对单个收藏的处理。
+*/
+type ImportAction string
+
+const (
+	ImportCreate ImportAction = "create"
+	ImportUpdate ImportAction = "update"
+	ImportSkip   ImportAction = "skip"
+)
+
+/*
+ImportDiff
+
+  - @brief 单个收藏在文件与服务器之间的差异。
+
+    【SubjectID】：条目ID。
+
+    【Name】：条目名。
+
+    【Action】：处理方式。
+
+    【Changes】：变化的字段，如"rate: 7 -> 8"。新建时为文件中的字段。
+
+    【File】：文件中的收藏。
+
+    【Server】：服务器上的收藏，不存在时为nil。
+*/
+type ImportDiff struct {
+	SubjectID int
+	Name      string
+	Action    ImportAction
+	Changes   []string
+	File      UserSubjectCollection
+	Server    *UserSubjectCollection
+}
+
+/*
+ImportOptions
+
+  - @brief 恢复收藏的选项。
+
+    【UserName】：当前token对应的用户名，用于获取服务器上的收藏。为空时使用导出文件中的用户名。
+
+    【DryRun】：为true时只计算差异，不修改服务器。
+
+    【Policy】：冲突处理方式。
+
+    【RestoreEpisodes】：为true时按ep_status将动漫、三次元条目的前若干个本篇章节标记为看过。
+
+    【CheckpointPath】：进度文件路径。为空时不记录进度；不为空时会跳过进度文件中已完成的条目，并在每个条目完成后更新进度文件。
+
+    【Client】：http.Client对象，为nil时使用http.DefaultClient。
+*/
+type ImportOptions struct {
+	UserName        string
+	DryRun          bool
+	Policy          ConflictPolicy
+	RestoreEpisodes bool
+	CheckpointPath  string
+	Client          *http.Client
+}
+
+/*
+ImportReport
+
+  - @brief 恢复收藏的结果。
+
+    【Diffs】：每个收藏的差异。
+
+    【Applied】：已写入服务器的收藏数。
+
+    【Resumed】：根据进度文件跳过的收藏数。
+
+    【Failed】：写入失败的收藏，键为条目ID。
+*/
+type ImportReport struct {
+	Diffs   []ImportDiff
+	Applied int
+	Resumed int
+	Failed  map[int]error
+}
+
+/*
+ * @brief 进度文件格式
+ */
+type importCheckpoint struct {
+	Done []int `json:"done"`
+}
+
+func loadImportCheckpoint(path string) (map[int]bool, error) {
+	done := make(map[int]bool)
+	if len(path) == 0 {
+		return done, nil
+	}
+	var checkpoint importCheckpoint
+	if _, err := readJSONFile(path, &checkpoint); err != nil {
+		return nil, err
+	}
+	for _, id := range checkpoint.Done {
+		done[id] = true
+	}
+	return done, nil
+}
+
+func saveImportCheckpoint(path string, done map[int]bool) error {
+	if len(path) == 0 {
+		return nil
+	}
+	checkpoint := importCheckpoint{Done: make([]int, 0, len(done))}
+	for id := range done {
+		checkpoint.Done = append(checkpoint.Done, id)
+	}
+	sort.Ints(checkpoint.Done)
+	return writeJSONFile(path, checkpoint)
+}
+
+/*
+diffCollection
+
+  - @brief 比较文件中的收藏和服务器上的收藏
+
+  - @param
+
+    【file】：文件中的收藏
+
+    【server】：服务器上的收藏，可以为nil
+
+  - @return 返回变化的字段。
+*/
+func diffCollection(file UserSubjectCollection, server *UserSubjectCollection) []string {
+	var base UserSubjectCollection
+	if server != nil {
+		base = *server
+	}
+	var changes []string
+	add := func(name string, from, to interface{}) {
+		if fmt.Sprint(from) != fmt.Sprint(to) {
+			changes = append(changes, fmt.Sprintf("%s: %v -> %v", name, from, to))
+		}
+	}
+	add("type", CollectionTypeName(base.Type), CollectionTypeName(file.Type))
+	add("rate", base.Rate, file.Rate)
+	add("comment", strconv.Quote(base.Comment), strconv.Quote(file.Comment))
+	add("tags", sortedTags(base.Tags), sortedTags(file.Tags))
+	add("ep_status", base.EpStatus, file.EpStatus)
+	add("vol_status", base.VolStatus, file.VolStatus)
+	add("private", base.Private, file.Private)
+	return changes
+}
+
+func sortedTags(tags []string) string {
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
+
+/*
+PlanImport
+
+  - @brief 计算导出结果与服务器上当前收藏的差异，不修改服务器。
+
+  - @param
+
+    【export】：导出结果。
+
+    【opts】：选项。
+
+  - @return 返回一个[]ImportDiff和一个err。
+
+  - @retval []ImportDiff是每个收藏的差异，err表示错误。如果err为nil，则没有错误。
+*/
+func PlanImport(export *CollectionExport, opts ImportOptions) ([]ImportDiff, error) {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	userName := opts.UserName
+	if len(userName) == 0 {
+		userName = export.UserName
+	}
+	if len(userName) == 0 {
+		errMsg := errors.New("PlanImport：缺少用户名")
+		return nil, errMsg
+	}
+
+	current, err := ExportCollections(userName, client)
+	if err != nil {
+		return nil, err
+	}
+	server := make(map[int]*UserSubjectCollection, len(current.Collections))
+	for i := range current.Collections {
+		server[current.Collections[i].SubjectID] = &current.Collections[i]
+	}
+
+	diffs := make([]ImportDiff, 0, len(export.Collections))
+	for _, file := range export.Collections {
+		diff := ImportDiff{
+			SubjectID: file.SubjectID,
+			Name:      file.Subject.Name,
+			File:      file,
+			Server:    server[file.SubjectID],
+			Changes:   diffCollection(file, server[file.SubjectID]),
+		}
+		switch {
+		case diff.Server == nil:
+			diff.Action = ImportCreate
+		case len(diff.Changes) == 0:
+			diff.Action = ImportSkip
+		case opts.Policy == ConflictFileWins:
+			diff.Action = ImportUpdate
+		case opts.Policy == ConflictNewestWins && file.UpdatedAt.After(diff.Server.UpdatedAt):
+			diff.Action = ImportUpdate
+		default:
+			diff.Action = ImportSkip
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+/*
+RestoreCollections
+
+  - @brief 从导出结果恢复当前用户的收藏。
+    通过AddOrEditCollectionsSubjectsInUsersByID写入条目收藏，RestoreEpisodes为true时通过GetCollectionsSubjectsEpisodesInfo写入章节进度。
+    DryRun为true时只返回差异。设置了CheckpointPath时，中断后再次调用会从上次的进度继续。
+
+    API：/v0/users/-/collections/{subject_id}
+
+  - @param
+
+    【export】：导出结果，可以由LoadCollectionExportJSON或LoadCollectionExportCSV读取。
+
+    【opts】：选项。
+
+  - @return 返回一个*ImportReport和一个err。
+
+  - @retval *ImportReport是恢复结果，err表示错误。单个条目写入失败不会返回err，而是记录在ImportReport.Failed中。
+*/
+func RestoreCollections(export *CollectionExport, opts ImportOptions) (*ImportReport, error) {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	diffs, err := PlanImport(export, opts)
+	if err != nil {
+		return nil, err
+	}
+	report := &ImportReport{Diffs: diffs, Failed: make(map[int]error)}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	done, err := loadImportCheckpoint(opts.CheckpointPath)
+	if err != nil {
+		errMsg := errors.New("RestoreCollections：读取进度文件失败")
+		return nil, errMsg
+	}
+
+	for _, diff := range diffs {
+		if diff.Action == ImportSkip {
+			continue
+		}
+		if done[diff.SubjectID] {
+			report.Resumed++
+			continue
+		}
+		if err = applyImport(diff.File, opts.RestoreEpisodes, client); err != nil {
+			report.Failed[diff.SubjectID] = err
+			continue
+		}
+		report.Applied++
+		done[diff.SubjectID] = true
+		if err = saveImportCheckpoint(opts.CheckpointPath, done); err != nil {
+			errMsg := errors.New("RestoreCollections：写入进度文件失败")
+			return report, errMsg
+		}
+	}
+	return report, nil
+}
+
+/*
+applyImport
+
+  - @brief 将单个收藏写入服务器
+
+  - @param
+
+    【c】：收藏
+
+    【restoreEpisodes】：是否写入章节进度
+
+    【client】：http.Client对象
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func applyImport(c UserSubjectCollection, restoreEpisodes bool, client *http.Client) error {
+	body := map[string]interface{}{
+		"type":    c.Type,
+		"rate":    c.Rate,
+		"comment": c.Comment,
+		"private": c.Private,
+		"tags":    c.Tags,
+	}
+	if c.Tags == nil {
+		body["tags"] = []string{}
+	}
+	// 只能直接修改书籍类条目的完成度
+	if c.SubjectType == 1 {
+		body["ep_status"] = c.EpStatus
+		body["vol_status"] = c.VolStatus
+	}
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	subID := strconv.Itoa(c.SubjectID)
+	if _, err = AddOrEditCollectionsSubjectsInUsersByID(subID, string(requestBody), client); err != nil {
+		return err
+	}
+
+	if !restoreEpisodes || c.EpStatus <= 0 || (c.SubjectType != 2 && c.SubjectType != 6) {
+		return nil
+	}
+	episodes, err := GetAllEpisodesBySubjectID(subID, "本篇", client)
+	if err != nil {
+		return err
+	}
+	sort.Slice(episodes, func(i, j int) bool { return episodes[i].Sort < episodes[j].Sort })
+	if len(episodes) > c.EpStatus {
+		episodes = episodes[:c.EpStatus]
+	}
+	if len(episodes) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(episodes))
+	for _, e := range episodes {
+		ids = append(ids, e.ID)
+	}
+	requestBody, err = json.Marshal(map[string]interface{}{"episode_id": ids, "type": 2})
+	if err != nil {
+		return err
+	}
+	_, err = GetCollectionsSubjectsEpisodesInfo(subID, string(requestBody), client)
+	return err
+}
@@ -0,0 +1,209 @@
+/**
+ * @file 	infobox.go
+ * @brief 	条目、角色、人物的infobox
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+/*
+ * @brief infobox列表值中的一项，如别名列表中的{"k": "英文", "v": "..."}。K可以为空
+ */
+type InfoboxValue struct {
+	K string `json:"k,omitempty"`
+	V string `json:"v"`
+}
+
+/*
+InfoboxItem
+
+  - @brief infobox中的一个字段。值为字符串时Values为nil，值为列表时Value为空。
+*/
+type InfoboxItem struct {
+	Key    string
+	Value  string
+	Values []InfoboxValue
+}
+
+/*
+IsList
+
+  - @brief 值是否为列表。
+
+  - @return 返回一个bool。
+*/
+func (item InfoboxItem) IsList() bool {
+	return item.Values != nil
+}
+
+/*
+Strings
+
+  - @brief 返回字段的全部值。值为字符串时返回只有一个元素的切片，值为列表时返回列表中每一项的v。
+
+  - @return 返回一个[]string。
+*/
+func (item InfoboxItem) Strings() []string {
+	if !item.IsList() {
+		return []string{item.Value}
+	}
+	values := make([]string, 0, len(item.Values))
+	for _, v := range item.Values {
+		values = append(values, v.V)
+	}
+	return values
+}
+
+func (item *InfoboxItem) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Key   string          `json:"key"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	item.Key, item.Value, item.Values = raw.Key, "", nil
+
+	value := strings.TrimSpace(string(raw.Value))
+	switch {
+	case len(value) == 0 || value == "null":
+		return nil
+	case value[0] == '[':
+		item.Values = []InfoboxValue{}
+		return json.Unmarshal(raw.Value, &item.Values)
+	case value[0] == '"':
+		return json.Unmarshal(raw.Value, &item.Value)
+	default:
+		// 数字等其他类型按原样保存为字符串
+		item.Value = value
+		return nil
+	}
+}
+
+func (item InfoboxItem) MarshalJSON() ([]byte, error) {
+	if item.IsList() {
+		return json.Marshal(struct {
+			Key   string         `json:"key"`
+			Value []InfoboxValue `json:"value"`
+		}{item.Key, item.Values})
+	}
+	return json.Marshal(struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}{item.Key, item.Value})
+}
+
+/*
+Infobox
+
+  - @brief 条目、角色、人物的infobox，保持原有字段顺序。
+*/
+type Infobox []InfoboxItem
+
+func (b *Infobox) UnmarshalJSON(data []byte) error {
+	if strings.TrimSpace(string(data)) == "null" {
+		*b = nil
+		return nil
+	}
+	var items []InfoboxItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return errors.New("Infobox：解析infobox失败")
+	}
+	*b = items
+	return nil
+}
+
+/*
+Get
+
+  - @brief 获取字段的第一个值。值为列表时返回列表第一项的v。
+
+  - @param
+
+    【key】：字段名，如"导演"。
+
+  - @return 返回字段值，字段不存在时返回空字符串。
+*/
+func (b Infobox) Get(key string) string {
+	for _, item := range b {
+		if item.Key != key {
+			continue
+		}
+		if values := item.Strings(); len(values) != 0 {
+			return values[0]
+		}
+		return ""
+	}
+	return ""
+}
+
+/*
+GetAll
+
+  - @brief 获取字段的全部值，包括重复出现的同名字段和列表中的每一项。
+
+  - @param
+
+    【key】：字段名，如"别名"。
+
+  - @return 返回一个[]string，字段不存在时返回nil。
+*/
+func (b Infobox) GetAll(key string) []string {
+	var values []string
+	for _, item := range b {
+		if item.Key == key {
+			values = append(values, item.Strings()...)
+		}
+	}
+	return values
+}
+
+/*
+ * @brief 视为别名的字段
+ */
+var infoboxAliasKeys = map[string]bool{
+	"中文名": true, "简体中文名": true, "第二中文名": true, "别名": true,
+	"英文名": true, "日文名": true, "纯假名": true, "罗马字": true, "外文名": true,
+}
+
+/*
+Aliases
+
+  - @brief 获取全部别名，包括中文名、英文名、日文名、罗马字和别名列表。
+    会去掉首尾空白、空值和重复值，保持在infobox中的出现顺序。
+
+  - @param
+
+    【exclude】：不需要的名称，如条目的name。
+
+  - @return 返回一个[]string。
+*/
+func (b Infobox) Aliases(exclude ...string) []string {
+	seen := make(map[string]bool)
+	for _, name := range exclude {
+		seen[strings.TrimSpace(name)] = true
+	}
+	var aliases []string
+	for _, item := range b {
+		if !infoboxAliasKeys[item.Key] {
+			continue
+		}
+		for _, value := range item.Strings() {
+			value = strings.TrimSpace(value)
+			if len(value) == 0 || seen[value] {
+				continue
+			}
+			seen[value] = true
+			aliases = append(aliases, value)
+		}
+	}
+	return aliases
+}
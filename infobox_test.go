@@ -0,0 +1,31 @@
+package lite_bangumi_api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInfoboxAliases(t *testing.T) {
+	b := Infobox{
+		{Key: "别名", Values: []InfoboxValue{{V: " まどマギ "}, {K: "英文", V: "Madoka Magica"}, {V: ""}}},
+		{Key: "放送开始", Value: "2011年1月7日"},
+		{Key: "中文名", Value: "魔法少女小圆"},
+		{Key: "日文名", Value: "魔法少女まどか☆マギカ"},
+		{Key: "英文名", Value: "Madoka Magica"},
+	}
+	tests := []struct {
+		name    string
+		exclude []string
+		want    []string
+	}{
+		{"infobox order", nil, []string{"まどマギ", "Madoka Magica", "魔法少女小圆", "魔法少女まどか☆マギカ"}},
+		{"exclude name", []string{"魔法少女まどか☆マギカ "}, []string{"まどマギ", "Madoka Magica", "魔法少女小圆"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Aliases(tt.exclude...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Aliases(%q) = %q, want %q", tt.exclude, got, tt.want)
+			}
+		})
+	}
+}
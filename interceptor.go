@@ -0,0 +1,93 @@
+/**
+ * @file 	interceptor.go
+ * @brief 	请求拦截器链
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"net/http"
+)
+
+/*
+RequestHandler
+
+  - @brief 发送请求并返回响应的函数。
+*/
+type RequestHandler func(req *http.Request) (*http.Response, error)
+
+/*
+Interceptor
+
+  - @brief 请求拦截器。所有API函数的请求都会经过拦截器链。
+
+    【op】：操作名，即API函数名，如SearchSubjectsById。
+
+    【req】：即将发送的请求，可以修改请求头等。
+
+    【next】：链中的下一个处理函数，拦截器需要调用next才会真正发送请求。
+*/
+type Interceptor func(op string, req *http.Request, next RequestHandler) (*http.Response, error)
+
+/*
+ * @brief 拦截器链，按顺序执行，第一个拦截器在最外层。
+ */
+var Interceptors []Interceptor
+
+type operationKey struct{}
+
+/*
+OperationFromContext
+
+  - @brief 从请求的context中获取操作名。可以在自定义的http.RoundTripper中使用。
+
+  - @param
+
+    【ctx】：请求的context。
+
+  - @return 返回操作名，不存在时返回空字符串。
+*/
+func OperationFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+/*
+doRequest
+
+  - @brief 经过拦截器链发送请求
+
+  - @param
+
+    【info】：请求信息，用于日志等
+
+    【req】：http.Request对象
+
+    【client】：http.Client对象
+
+  - @return 返回一个*http.Response和一个err。
+*/
+func doRequest(info *requestInfo, req *http.Request, client *http.Client) (*http.Response, error) {
+	op := info.op
+	req = req.WithContext(context.WithValue(req.Context(), operationKey{}, op))
+
+	handler := RequestHandler(func(req *http.Request) (*http.Response, error) {
+		if RateLimit != nil {
+			if err := RateLimit.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		return client.Do(req)
+	})
+	for i := len(Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := Interceptors[i], handler
+		handler = func(req *http.Request) (*http.Response, error) {
+			return interceptor(op, req, next)
+		}
+	}
+	return handler(req)
+}
@@ -0,0 +1,112 @@
+/**
+ * @file 	logging.go
+ * @brief 	基于log/slog的结构化请求日志
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/*
+ * @brief 定义日志相关的全局变量
+ *
+ * Logger为nil时不记录日志。
+ * 成功的请求使用LogSuccessLevel记录，失败的请求使用LogFailureLevel记录。
+ */
+var (
+	Logger          *slog.Logger
+	LogSuccessLevel = slog.LevelDebug
+	LogFailureLevel = slog.LevelWarn
+)
+
+/*
+ * @brief URL中需要隐藏的查询参数
+ */
+var redactedParams = []string{"token", "access_token", "refresh_token", "client_secret"}
+
+/*
+logRequest
+
+  - @brief API调用结束时记录日志
+
+  - @param
+
+    【info】：请求信息
+
+    【size】：响应体大小
+
+    【err】：API调用的错误
+*/
+func logRequest(info *requestInfo, size int, err error) {
+	if Logger == nil {
+		return
+	}
+
+	level := LogSuccessLevel
+	msg := "bangumi请求成功"
+	if err != nil {
+		level = LogFailureLevel
+		msg = "bangumi请求失败"
+	}
+	ctx := context.Background()
+	if !Logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("op", info.op),
+		slog.String("method", info.method),
+		slog.String("url", redactURL(info.url)),
+		slog.Int("status", info.status),
+		slog.Duration("latency", time.Since(info.start)),
+		slog.Int("size", size),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	Logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+/*
+redactURL
+
+  - @brief 隐藏URL中的token等敏感信息
+
+  - @param
+
+    【rawURL】：地址
+
+  - @return 返回隐藏敏感信息后的地址。
+*/
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if u.User != nil {
+		u.User = url.User("REDACTED")
+	}
+	query := u.Query()
+	changed := false
+	for key := range query {
+		for _, name := range redactedParams {
+			if strings.EqualFold(key, name) {
+				query.Set(key, "REDACTED")
+				changed = true
+			}
+		}
+	}
+	if changed {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
@@ -0,0 +1,377 @@
+/**
+ * @file 	matcher.go
+ * @brief 	将其他网站（MyAnimeList、AniList等）的条目匹配到Bangumi条目
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/*
+MatchQuery
+
+  - @brief 需要匹配的外部条目。
+
+    【Source】：来源，如mal、anilist，仅用于记录。
+
+    【ForeignID】：来源中的ID，仅用于记录。
+
+    【Title】：标题。
+
+    【AltTitles】：其他标题（日文名、英文名、别名等）。
+
+    【AirDate】：放送开始日期，格式为2006-01-02，可以为空。
+
+    【Episodes】：话数，0表示未知。
+
+    【SubjectType】：条目类型ID，0时为2（动漫）。
+*/
+type MatchQuery struct {
+	Source      string
+	ForeignID   string
+	Title       string
+	AltTitles   []string
+	AirDate     string
+	Episodes    int
+	SubjectType int
+}
+
+/*
+SubjectMatch
+
+  - @brief 候选条目。
+
+    【Aliases】：infobox中的别名，参与标题相似度计算。
+
+    【Confidence】：置信度，范围为0～1。
+
+    【Reasons】：评分依据。
+*/
+type SubjectMatch struct {
+	SubjectID  int
+	Name       string
+	NameCN     string
+	Date       string
+	Eps        int
+	Aliases    []string
+	Confidence float64
+	Reasons    []string
+}
+
+/*
+MatchResult
+
+  - @brief 匹配结果。
+
+    【Query】：匹配的外部条目。
+
+    【Candidates】：按置信度从高到低排列的候选条目。
+
+    【Ambiguous】：为true时表示最佳候选置信度不足或与次佳候选过于接近，需要人工确认。
+*/
+type MatchResult struct {
+	Query      MatchQuery
+	Candidates []SubjectMatch
+	Ambiguous  bool
+}
+
+/*
+ * @brief 匹配参数
+ */
+const (
+	matchSearchLimit     = 10
+	matchConfidentScore  = 0.8
+	matchAmbiguousMargin = 0.1
+	matchTitleWeight     = 0.6
+	matchDateWeight      = 0.25
+	matchEpisodesWeight  = 0.15
+)
+
+/*
+BestMatch
+
+  - @brief 返回置信度最高的候选条目。
+
+  - @return 返回一个SubjectMatch和一个bool。
+
+  - @retval bool为false表示没有候选条目或结果需要人工确认。
+*/
+func (r *MatchResult) BestMatch() (SubjectMatch, bool) {
+	if len(r.Candidates) == 0 {
+		return SubjectMatch{}, false
+	}
+	return r.Candidates[0], !r.Ambiguous
+}
+
+/*
+MatchSubject
+
+  - @brief 通过标题搜索Bangumi条目，并按标题相似度、放送日期、话数评分。
+
+    API：/v0/search/subjects、/search/subject/{keywords}
+
+  - @param
+
+    【query】：需要匹配的外部条目。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*MatchResult和一个err。
+
+  - @retval *MatchResult是匹配结果，err表示错误。如果err为nil，则没有错误。
+*/
+func MatchSubject(query MatchQuery, client *http.Client) (*MatchResult, error) {
+	subjectType := query.SubjectType
+	if subjectType == 0 {
+		subjectType = 2
+	}
+	titles := uniqueTitles(append([]string{query.Title}, query.AltTitles...))
+	if len(titles) == 0 {
+		errMsg := errors.New("MatchSubject：缺少标题")
+		return nil, errMsg
+	}
+
+	candidates := make(map[int]*SubjectMatch)
+	var searchErr error
+	for _, title := range titles {
+		if err := searchMatchCandidates(title, subjectType, candidates, client); err != nil {
+			searchErr = err
+		}
+	}
+	if len(candidates) == 0 && searchErr != nil {
+		return nil, searchErr
+	}
+
+	result := &MatchResult{Query: query}
+	for _, c := range candidates {
+		scoreMatch(c, query, titles)
+		result.Candidates = append(result.Candidates, *c)
+	}
+	sort.Slice(result.Candidates, func(i, j int) bool {
+		if result.Candidates[i].Confidence != result.Candidates[j].Confidence {
+			return result.Candidates[i].Confidence > result.Candidates[j].Confidence
+		}
+		return result.Candidates[i].SubjectID < result.Candidates[j].SubjectID
+	})
+
+	result.Ambiguous = len(result.Candidates) == 0 || result.Candidates[0].Confidence < matchConfidentScore
+	if len(result.Candidates) > 1 && result.Candidates[0].Confidence-result.Candidates[1].Confidence < matchAmbiguousMargin {
+		result.Ambiguous = true
+	}
+	return result, nil
+}
+
+/*
+searchMatchCandidates
+
+  - @brief 通过新旧两个搜索API搜索候选条目
+
+  - @param
+
+    【title】：标题
+
+    【subjectType】：条目类型ID
+
+    【candidates】：以条目ID为键的候选条目，搜索结果会合并进来
+
+    【client】：http.Client对象
+
+  - @return 返回一个err。两个搜索API都失败时才返回错误。
+*/
+func searchMatchCandidates(title string, subjectType int, candidates map[int]*SubjectMatch, client *http.Client) error {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"keyword": title,
+		"filter":  map[string]interface{}{"type": []int{subjectType}},
+	})
+	if err != nil {
+		return err
+	}
+	jsonData, v0Err := SearchSubjectsByName(fmt.Sprint(matchSearchLimit), "0", string(requestBody), client)
+	if v0Err == nil {
+		var page PagedSubject
+		if err = json.Unmarshal(jsonData, &page); err == nil {
+			for _, s := range page.Data {
+				c := matchCandidate(candidates, s.ID)
+				c.Name, c.NameCN, c.Date = s.Name, s.NameCN, s.Date
+				c.Aliases = s.Infobox.Aliases(s.Name, s.NameCN)
+				if s.Eps != 0 {
+					c.Eps = s.Eps
+				} else if s.TotalEpisodes != 0 {
+					c.Eps = s.TotalEpisodes
+				}
+			}
+		}
+	}
+
+	legacy, legacyErr := SearchAllSubjectsByNameSmall(title, SubjectTypeName(subjectType), "0", fmt.Sprint(matchSearchLimit), client)
+	if legacyErr == nil {
+		for _, s := range legacy.List {
+			c := matchCandidate(candidates, s.ID)
+			if len(c.Name) == 0 {
+				c.Name, c.NameCN, c.Date = s.Name, s.NameCN, s.AirDate
+			}
+		}
+	}
+
+	if v0Err != nil && legacyErr != nil {
+		return v0Err
+	}
+	return nil
+}
+
+func matchCandidate(candidates map[int]*SubjectMatch, id int) *SubjectMatch {
+	c, ok := candidates[id]
+	if !ok {
+		c = &SubjectMatch{SubjectID: id}
+		candidates[id] = c
+	}
+	return c
+}
+
+/*
+scoreMatch
+
+  - @brief 计算候选条目的置信度
+
+  - @param
+
+    【c】：候选条目
+
+    【query】：需要匹配的外部条目
+
+    【titles】：去重后的全部标题
+*/
+func scoreMatch(c *SubjectMatch, query MatchQuery, titles []string) {
+	c.Reasons = nil
+
+	titleScore, bestTitle, bestName := 0.0, "", ""
+	for _, title := range titles {
+		for _, name := range append([]string{c.Name, c.NameCN}, c.Aliases...) {
+			if s := titleSimilarity(title, name); s > titleScore {
+				titleScore, bestTitle, bestName = s, title, name
+			}
+		}
+	}
+	c.Reasons = append(c.Reasons, fmt.Sprintf("标题相似度%.2f（%s ~ %s）", titleScore, bestTitle, bestName))
+
+	dateScore := 0.5
+	queryDate, queryErr := time.Parse("2006-01-02", query.AirDate)
+	subjectDate, subjectErr := time.Parse("2006-01-02", c.Date)
+	if queryErr == nil && subjectErr == nil {
+		days := math.Abs(queryDate.Sub(subjectDate).Hours() / 24)
+		switch {
+		case days <= 7:
+			dateScore = 1
+		case days <= 31:
+			dateScore = 0.7
+		case queryDate.Year() == subjectDate.Year():
+			dateScore = 0.4
+		default:
+			dateScore = 0
+		}
+		c.Reasons = append(c.Reasons, fmt.Sprintf("放送日期相差%.0f天", days))
+	}
+
+	episodesScore := 0.5
+	if query.Episodes > 0 && c.Eps > 0 {
+		switch diff := query.Episodes - c.Eps; {
+		case diff == 0:
+			episodesScore = 1
+		case diff == 1 || diff == -1:
+			episodesScore = 0.7
+		default:
+			episodesScore = 0
+		}
+		c.Reasons = append(c.Reasons, fmt.Sprintf("话数%d/%d", query.Episodes, c.Eps))
+	}
+
+	c.Confidence = titleScore*matchTitleWeight + dateScore*matchDateWeight + episodesScore*matchEpisodesWeight
+}
+
+/*
+normalizeTitle
+
+  - @brief 标题归一化：转为小写，去掉空白和标点，全角字母数字转为半角
+
+  - @param
+
+    【title】：标题
+
+  - @return 返回归一化后的标题。
+*/
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if r >= '！' && r <= '～' {
+			r = r - '！' + '!'
+		}
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+/*
+titleSimilarity
+
+  - @brief 使用字符二元组的Dice系数计算标题相似度
+
+  - @param
+
+    【a】【b】：标题
+
+  - @return 返回相似度，范围为0～1。
+*/
+func titleSimilarity(a, b string) float64 {
+	ra, rb := []rune(normalizeTitle(a)), []rune(normalizeTitle(b))
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	if string(ra) == string(rb) {
+		return 1
+	}
+	if len(ra) == 1 || len(rb) == 1 {
+		return 0
+	}
+	bigrams := make(map[string]int)
+	for i := 0; i+1 < len(ra); i++ {
+		bigrams[string(ra[i:i+2])]++
+	}
+	common := 0
+	for i := 0; i+1 < len(rb); i++ {
+		key := string(rb[i : i+2])
+		if bigrams[key] > 0 {
+			bigrams[key]--
+			common++
+		}
+	}
+	return float64(2*common) / float64(len(ra)+len(rb)-2)
+}
+
+func uniqueTitles(titles []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, title := range titles {
+		key := normalizeTitle(title)
+		if len(key) == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, strings.TrimSpace(title))
+	}
+	return result
+}
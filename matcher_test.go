@@ -0,0 +1,136 @@
+package lite_bangumi_api
+
+import (
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Fate/Zero 2nd Season!", "fatezero2ndseason"},
+		{"ＡＢＣ　１２３", "abc123"},
+		{"魔法少女まどか☆マギカ", "魔法少女まどかマギカ"},
+		{" ~!? ", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeTitle(tt.title); got != tt.want {
+			t.Errorf("normalizeTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Madoka", "ＭＡＤＯＫＡ", 1},
+		{"abcd", "abce", 2.0 / 3},
+		{"abcd", "wxyz", 0},
+		{"a", "b", 0},
+		{"", "abc", 0},
+		{"aaaa", "aa", 2.0 / 4},
+	}
+	for _, tt := range tests {
+		if got := titleSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("titleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestScoreMatch(t *testing.T) {
+	query := MatchQuery{Title: "Madoka Magica", AirDate: "2011-01-07", Episodes: 12}
+	tests := []struct {
+		name      string
+		candidate SubjectMatch
+		query     MatchQuery
+		want      float64
+	}{
+		{"exact match", SubjectMatch{Name: "Madoka Magica", Date: "2011-01-07", Eps: 12}, query, 1},
+		{"unknown date and episodes", SubjectMatch{Name: "Madoka Magica"}, query, 0.6 + 0.5*0.25 + 0.5*0.15},
+		{"close date and episodes", SubjectMatch{Name: "Madoka Magica", Date: "2011-01-27", Eps: 13}, query, 0.6 + 0.7*0.25 + 0.7*0.15},
+		{"same year", SubjectMatch{Name: "Madoka Magica", Date: "2011-10-01", Eps: 3}, query, 0.6 + 0.4*0.25},
+		{"different year", SubjectMatch{Name: "Madoka Magica", Date: "2012-10-06", Eps: 1}, query, 0.6},
+		{"alias match", SubjectMatch{Name: "魔法少女まどか☆マギカ", Aliases: []string{"Madoka Magica"}, Date: "2011-01-07", Eps: 12}, query, 1},
+		{"alt title match", SubjectMatch{Name: "魔法少女まどか☆マギカ", Date: "2011-01-07", Eps: 12},
+			MatchQuery{Title: "Puella Magi", AltTitles: []string{"魔法少女まどかマギカ"}, AirDate: "2011-01-07", Episodes: 12}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.candidate
+			scoreMatch(&c, tt.query, uniqueTitles(append([]string{tt.query.Title}, tt.query.AltTitles...)))
+			if math.Abs(c.Confidence-tt.want) > 1e-9 {
+				t.Errorf("Confidence = %v, want %v (%q)", c.Confidence, tt.want, c.Reasons)
+			}
+		})
+	}
+}
+
+func TestMatchSubject(t *testing.T) {
+	const legacyNotFound = `{"request":"","code":404,"error":"Not Found"}`
+	tests := []struct {
+		name          string
+		v0Status      int
+		v0Body        string
+		legacyBody    string
+		query         MatchQuery
+		wantBest      int
+		wantAmbiguous bool
+	}{
+		{
+			name:     "confident match",
+			v0Status: http.StatusOK,
+			v0Body: `{"total":2,"data":[
+				{"id":1,"name":"Madoka Magica","date":"2011-01-07","eps":12},
+				{"id":2,"name":"Madoka Magica Movie","date":"2012-10-06","eps":1}]}`,
+			legacyBody: legacyNotFound,
+			query:      MatchQuery{Title: "Madoka Magica", AirDate: "2011-01-07", Episodes: 12},
+			wantBest:   1,
+		},
+		{
+			name:     "close candidates are ambiguous",
+			v0Status: http.StatusOK,
+			v0Body: `{"total":2,"data":[
+				{"id":1,"name":"Madoka Magica","date":"2011-01-07","eps":12},
+				{"id":2,"name":"Madoka Magica","date":"2011-01-08","eps":12}]}`,
+			legacyBody:    legacyNotFound,
+			query:         MatchQuery{Title: "Madoka Magica", AirDate: "2011-01-07", Episodes: 12},
+			wantBest:      1,
+			wantAmbiguous: true,
+		},
+		{
+			name:       "legacy search when v0 fails",
+			v0Status:   http.StatusInternalServerError,
+			v0Body:     `{}`,
+			legacyBody: `{"results":1,"list":[{"id":3,"name":"Madoka Magica","air_date":"2011-01-07"}]}`,
+			query:      MatchQuery{Title: "Madoka Magica", AirDate: "2011-01-07"},
+			wantBest:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newMockAPI(t, func(req *http.Request) (int, string) {
+				if req.URL.Path == "/v0/search/subjects" {
+					return tt.v0Status, tt.v0Body
+				}
+				return http.StatusOK, tt.legacyBody
+			})
+			result, err := MatchSubject(tt.query, http.DefaultClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			best, ok := result.BestMatch()
+			if best.SubjectID != tt.wantBest || ok == tt.wantAmbiguous || result.Ambiguous != tt.wantAmbiguous {
+				t.Errorf("best = %d ok %v ambiguous %v, want %d ambiguous %v", best.SubjectID, ok, result.Ambiguous, tt.wantBest, tt.wantAmbiguous)
+			}
+		})
+	}
+
+	if _, err := MatchSubject(MatchQuery{Title: " ☆ "}, http.DefaultClient); err == nil {
+		t.Error("MatchSubject without a title: err = nil, want an error")
+	}
+}
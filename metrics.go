@@ -0,0 +1,171 @@
+/**
+ * @file 	metrics.go
+ * @brief 	请求统计接口以及expvar实现
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+Metrics
+
+  - @brief 请求统计接口。每次API调用结束时都会被调用。
+
+    【op】：操作名，即API函数名，如SearchSubjectsById。
+
+    【method】：方法。
+
+    【statusClass】：返回码类别，如2xx、4xx、5xx。没有收到响应时为error。
+*/
+type Metrics interface {
+	// IncRequest 请求计数
+	IncRequest(op, method, statusClass string)
+	// IncError 失败请求计数（包括返回码错误、连接失败等）
+	IncError(op, method, statusClass string)
+	// ObserveLatency 请求耗时
+	ObserveLatency(op, method, statusClass string, latency time.Duration)
+}
+
+/*
+ * @brief 统计接口，为nil时不统计
+ */
+var RequestMetrics Metrics
+
+/*
+statusClass
+
+  - @brief 返回码类别
+
+  - @param
+
+    【status】：返回码，0表示没有收到响应
+
+  - @return 返回如2xx的字符串，没有收到响应时返回error。
+*/
+func statusClass(status int) string {
+	if status <= 0 {
+		return "error"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+/*
+recordMetrics
+
+  - @brief API调用结束时记录统计信息
+
+  - @param
+
+    【info】：请求信息
+
+    【err】：API调用的错误
+*/
+func recordMetrics(info *requestInfo, err error) {
+	if RequestMetrics == nil {
+		return
+	}
+	class := statusClass(info.status)
+	RequestMetrics.IncRequest(info.op, info.method, class)
+	if err != nil {
+		RequestMetrics.IncError(info.op, info.method, class)
+	}
+	RequestMetrics.ObserveLatency(info.op, info.method, class, time.Since(info.start))
+}
+
+/*
+ * @brief 耗时直方图的分桶（秒）
+ */
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+/*
+ExpvarMetrics
+
+  - @brief 以expvar格式输出统计信息的Metrics实现，可以通过/debug/vars查看。
+
+    输出格式如下（键为"操作名 方法 返回码类别"）：
+
+    {
+    "requests": {"SearchSubjectsById GET 2xx": 10},
+    "errors": {"SearchSubjectsById GET 4xx": 1},
+    "latency_seconds": {"SearchSubjectsById GET 2xx": {"le_0.05": 2, ..., "le_+Inf": 10, "sum": 1.2, "count": 10}}
+    }
+*/
+type ExpvarMetrics struct {
+	requests *expvar.Map
+	errors   *expvar.Map
+	latency  *expvar.Map
+	buckets  []float64
+	mu       sync.Mutex
+}
+
+/*
+NewExpvarMetrics
+
+  - @brief 创建ExpvarMetrics并以name发布到expvar。同一个name只能发布一次，否则会panic。
+
+  - @param
+
+    【name】：expvar变量名，如bangumi。
+
+    【buckets】：耗时直方图的分桶（秒，从小到大），为nil时使用DefaultLatencyBuckets。
+
+  - @return 返回一个*ExpvarMetrics。
+*/
+func NewExpvarMetrics(name string, buckets []float64) *ExpvarMetrics {
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	m := &ExpvarMetrics{
+		requests: new(expvar.Map).Init(),
+		errors:   new(expvar.Map).Init(),
+		latency:  new(expvar.Map).Init(),
+		buckets:  buckets,
+	}
+	root := expvar.NewMap(name)
+	root.Set("requests", m.requests)
+	root.Set("errors", m.errors)
+	root.Set("latency_seconds", m.latency)
+	return m
+}
+
+func metricsKey(op, method, statusClass string) string {
+	return op + " " + method + " " + statusClass
+}
+
+func (m *ExpvarMetrics) IncRequest(op, method, statusClass string) {
+	m.requests.Add(metricsKey(op, method, statusClass), 1)
+}
+
+func (m *ExpvarMetrics) IncError(op, method, statusClass string) {
+	m.errors.Add(metricsKey(op, method, statusClass), 1)
+}
+
+func (m *ExpvarMetrics) ObserveLatency(op, method, statusClass string, latency time.Duration) {
+	key := metricsKey(op, method, statusClass)
+	m.mu.Lock()
+	histogram, ok := m.latency.Get(key).(*expvar.Map)
+	if !ok {
+		histogram = new(expvar.Map).Init()
+		m.latency.Set(key, histogram)
+	}
+	m.mu.Unlock()
+
+	seconds := latency.Seconds()
+	for _, bound := range m.buckets {
+		if seconds <= bound {
+			histogram.Add("le_"+strconv.FormatFloat(bound, 'g', -1, 64), 1)
+		}
+	}
+	histogram.Add("le_+Inf", 1)
+	histogram.AddFloat("sum", seconds)
+	histogram.Add("count", 1)
+}
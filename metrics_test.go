@@ -0,0 +1,118 @@
+package lite_bangumi_api
+
+import (
+	"expvar"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+/*
+ * @brief 记录调用的Metrics
+ */
+type recordingMetrics struct {
+	calls []string
+}
+
+func (m *recordingMetrics) IncRequest(op, method, statusClass string) {
+	m.calls = append(m.calls, "request "+metricsKey(op, method, statusClass))
+}
+
+func (m *recordingMetrics) IncError(op, method, statusClass string) {
+	m.calls = append(m.calls, "error "+metricsKey(op, method, statusClass))
+}
+
+func (m *recordingMetrics) ObserveLatency(op, method, statusClass string, latency time.Duration) {
+	m.calls = append(m.calls, "latency "+metricsKey(op, method, statusClass))
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{0, "error"},
+		{-1, "error"},
+		{200, "2xx"},
+		{204, "2xx"},
+		{404, "4xx"},
+		{503, "5xx"},
+	}
+	for _, tt := range tests {
+		if got := statusClass(tt.status); got != tt.want {
+			t.Errorf("statusClass(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRecordMetrics(t *testing.T) {
+	metrics := &recordingMetrics{}
+	saved := RequestMetrics
+	RequestMetrics = metrics
+	t.Cleanup(func() { RequestMetrics = saved })
+	newMockAPI(t, func(req *http.Request) (int, string) {
+		if req.URL.Path == "/v0/subjects/1" {
+			return http.StatusOK, `{"id":1}`
+		}
+		return http.StatusNotFound, `{}`
+	})
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+		want    []string
+	}{
+		{"success", "1", false, []string{"request SearchSubjectsById GET 2xx", "latency SearchSubjectsById GET 2xx"}},
+		{"not found", "2", true, []string{"request SearchSubjectsById GET 4xx", "error SearchSubjectsById GET 4xx", "latency SearchSubjectsById GET 4xx"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics.calls = nil
+			_, err := SearchSubjectsById(tt.id, http.DefaultClient)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(metrics.calls, tt.want) {
+				t.Errorf("calls = %q, want %q", metrics.calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	m := NewExpvarMetrics("lite_bangumi_api_test", []float64{0.1, 1})
+	m.IncRequest("op", "GET", "2xx")
+	m.IncRequest("op", "GET", "2xx")
+	m.IncError("op", "GET", "5xx")
+	m.ObserveLatency("op", "GET", "2xx", 50*time.Millisecond)
+	m.ObserveLatency("op", "GET", "2xx", 500*time.Millisecond)
+	m.ObserveLatency("op", "GET", "2xx", 2*time.Second)
+
+	if got := m.requests.Get("op GET 2xx").String(); got != "2" {
+		t.Errorf("requests = %s, want 2", got)
+	}
+	if got := m.errors.Get("op GET 5xx").String(); got != "1" {
+		t.Errorf("errors = %s, want 1", got)
+	}
+	histogram := m.latency.Get("op GET 2xx").(*expvar.Map)
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"le_0.1", "1"},
+		{"le_1", "2"},
+		{"le_+Inf", "3"},
+		{"count", "3"},
+		{"sum", "2.55"},
+	}
+	for _, tt := range tests {
+		if got := histogram.Get(tt.key).String(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.key, got, tt.want)
+		}
+	}
+	if expvar.Get("lite_bangumi_api_test") == nil {
+		t.Error("metrics were not published")
+	}
+}
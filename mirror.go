@@ -0,0 +1,627 @@
+/**
+ * @file 	mirror.go
+ * @brief 	用户收藏的本地镜像，支持增量同步和离线读取
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+CollectionReader
+
+  - @brief 读取用户收藏的接口。OnlineCollections通过API读取，Mirror从本地镜像读取，两者可以互换。
+*/
+type CollectionReader interface {
+	// Collections 获取某个条目类型、某个收藏类型下的全部收藏，typeName为空时获取全部收藏类型
+	Collections(subjectTypeName, typeName string) ([]UserSubjectCollection, error)
+	// Collection 获取单个条目的收藏
+	Collection(subjectID int) (*UserSubjectCollection, error)
+	// EpisodeCollections 获取条目中本篇的章节收藏
+	EpisodeCollections(subjectID int) ([]UserEpisodeCollection, error)
+	// CharacterCollections 获取角色收藏
+	CharacterCollections() ([]UserCharacterCollection, error)
+	// PersonCollections 获取人物收藏
+	PersonCollections() ([]UserPersonCollection, error)
+}
+
+/*
+OnlineCollections
+
+  - @brief 通过API读取用户收藏。EpisodeCollections需要 access token，且只能读取token对应的用户。
+*/
+type OnlineCollections struct {
+	UserName string
+	Client   *http.Client
+}
+
+func (o *OnlineCollections) client() *http.Client {
+	if o.Client == nil {
+		return http.DefaultClient
+	}
+	return o.Client
+}
+
+/*
+Collections
+
+  - @brief 与GetAllCollectionsByUserName相同。
+
+    API：/v0/users/{username}/collections
+*/
+func (o *OnlineCollections) Collections(subjectTypeName, typeName string) ([]UserSubjectCollection, error) {
+	return GetAllCollectionsByUserName(o.UserName, subjectTypeName, typeName, o.client())
+}
+
+/*
+Collection
+
+  - @brief 与SearchCollectionsByID相同，返回解析后的结果。
+
+    API：/v0/users/{username}/collections/{subject_id}
+*/
+func (o *OnlineCollections) Collection(subjectID int) (*UserSubjectCollection, error) {
+	jsonData, err := SearchCollectionsByID(o.UserName, strconv.Itoa(subjectID), o.client())
+	if err != nil {
+		return nil, err
+	}
+	var c UserSubjectCollection
+	if err = json.Unmarshal(jsonData, &c); err != nil {
+		errMsg := errors.New("Collection：解析返回体失败")
+		return nil, errMsg
+	}
+	return &c, nil
+}
+
+/*
+EpisodeCollections
+
+  - @brief 与GetAllUserEpisodeCollections相同，只获取本篇。
+
+    API：/v0/users/-/collections/{subject_id}/episodes
+*/
+func (o *OnlineCollections) EpisodeCollections(subjectID int) ([]UserEpisodeCollection, error) {
+	return GetAllUserEpisodeCollections(strconv.Itoa(subjectID), "本篇", o.client())
+}
+
+/*
+CharacterCollections
+
+  - @brief 与GetAllCharacterCollectionsByUserName相同。
+
+    API：/v0/users/{username}/collections/-/characters
+*/
+func (o *OnlineCollections) CharacterCollections() ([]UserCharacterCollection, error) {
+	return GetAllCharacterCollectionsByUserName(o.UserName, o.client())
+}
+
+/*
+PersonCollections
+
+  - @brief 与GetAllPersonCollectionsByUserName相同。
+
+    API：/v0/users/{username}/collections/-/persons
+*/
+func (o *OnlineCollections) PersonCollections() ([]UserPersonCollection, error) {
+	return GetAllPersonCollectionsByUserName(o.UserName, o.client())
+}
+
+/*
+ * @brief 镜像目录中的文件
+ */
+const (
+	mirrorMetaFile       = "meta.json"
+	mirrorSubjectsFile   = "subjects.json"
+	mirrorEpisodesFile   = "episodes.json"
+	mirrorCharactersFile = "characters.json"
+	mirrorPersonsFile    = "persons.json"
+)
+
+/*
+ * @brief 镜像的元数据，Watermarks的键为条目类型ID，值为已同步的最新收藏更新时间
+ */
+type mirrorMeta struct {
+	UserName   string               `json:"username"`
+	SyncedAt   time.Time            `json:"synced_at"`
+	Watermarks map[string]time.Time `json:"watermarks"`
+}
+
+/*
+MirrorOptions
+
+  - @brief 本地镜像的选项。
+
+    【Dir】：镜像目录，不存在时会被创建。
+
+    【UserName】：用户名。
+
+    【SubjectTypes】：同步的条目类型名，为nil时为全部条目类型。
+
+    【SyncEpisodes】：是否同步动漫、三次元条目的章节收藏，需要 access token，且UserName必须是token对应的用户。
+
+    【Concurrency】：同步章节收藏的最大并发数，小于等于0时为4。
+
+    【Client】：http.Client对象，为nil时使用http.DefaultClient。
+*/
+type MirrorOptions struct {
+	Dir          string
+	UserName     string
+	SubjectTypes []string
+	SyncEpisodes bool
+	Concurrency  int
+	Client       *http.Client
+}
+
+/*
+SyncReport
+
+  - @brief 一次同步的结果。
+
+    【Full】：是否为全量同步。
+
+    【Updated】：新增或更新的条目收藏数。
+
+    【Removed】：删除的条目收藏数，只有全量同步会检查删除。
+
+    【Episodes】：同步了章节收藏的条目数。
+
+    【Characters】【Persons】：角色、人物收藏数。
+*/
+type SyncReport struct {
+	Full       bool
+	Updated    int
+	Removed    int
+	Episodes   int
+	Characters int
+	Persons    int
+	SyncedAt   time.Time
+}
+
+/*
+Mirror
+
+  - @brief 用户收藏的本地镜像，保存在目录中的JSON文件里。实现了CollectionReader，可以离线读取。可以并发使用。
+*/
+type Mirror struct {
+	opts MirrorOptions
+
+	mu         sync.RWMutex
+	meta       mirrorMeta
+	subjects   map[int]UserSubjectCollection
+	episodes   map[int][]UserEpisodeCollection
+	characters []UserCharacterCollection
+	persons    []UserPersonCollection
+}
+
+/*
+OpenMirror
+
+  - @brief 打开本地镜像，读取已经同步的数据。目录为空时为空镜像，第一次Sync会全量同步。
+
+  - @param
+
+    【opts】：选项。
+
+  - @return 返回一个*Mirror和一个err。
+
+  - @retval *Mirror是本地镜像，err表示错误。如果err为nil，则没有错误。
+*/
+func OpenMirror(opts MirrorOptions) (*Mirror, error) {
+	if len(opts.Dir) == 0 || len(opts.UserName) == 0 {
+		errMsg := errors.New("OpenMirror：缺少目录或用户名")
+		return nil, errMsg
+	}
+	if opts.SubjectTypes == nil {
+		opts.SubjectTypes = SubjectTypeNames
+	}
+	for _, name := range opts.SubjectTypes {
+		if SubjectTypeID(name) == 0 {
+			errMsg := errors.New("OpenMirror：不匹配的条目类型")
+			return nil, errMsg
+		}
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		errMsg := errors.New("OpenMirror：创建目录失败")
+		return nil, errMsg
+	}
+
+	m := &Mirror{
+		opts:     opts,
+		meta:     mirrorMeta{UserName: opts.UserName, Watermarks: make(map[string]time.Time)},
+		subjects: make(map[int]UserSubjectCollection),
+		episodes: make(map[int][]UserEpisodeCollection),
+	}
+	var subjects []UserSubjectCollection
+	var episodes map[string][]UserEpisodeCollection
+	files := []struct {
+		name string
+		v    interface{}
+	}{
+		{mirrorMetaFile, &m.meta},
+		{mirrorSubjectsFile, &subjects},
+		{mirrorEpisodesFile, &episodes},
+		{mirrorCharactersFile, &m.characters},
+		{mirrorPersonsFile, &m.persons},
+	}
+	for _, f := range files {
+		if _, err := readJSONFile(filepath.Join(opts.Dir, f.name), f.v); err != nil {
+			errMsg := errors.New("OpenMirror：读取" + f.name + "失败")
+			return nil, errMsg
+		}
+	}
+	if len(m.meta.UserName) != 0 && m.meta.UserName != opts.UserName {
+		errMsg := errors.New("OpenMirror：镜像属于其他用户")
+		return nil, errMsg
+	}
+	m.meta.UserName = opts.UserName
+	if m.meta.Watermarks == nil {
+		m.meta.Watermarks = make(map[string]time.Time)
+	}
+	m.subjects = collectionMap(subjects)
+	for key, list := range episodes {
+		if id, err := strconv.Atoi(key); err == nil {
+			m.episodes[id] = list
+		}
+	}
+	return m, nil
+}
+
+/*
+LastSync
+
+  - @brief 获取上一次同步的时间，没有同步过时为零值。
+
+  - @return 返回一个time.Time。
+*/
+func (m *Mirror) LastSync() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.meta.SyncedAt
+}
+
+/*
+collectionsUpdatedSince
+
+  - @brief 按更新时间从新到旧获取收藏，遇到早于since的收藏时停止翻页
+
+  - @param
+
+    【userName】：用户名
+
+    【subjectTypeName】：条目类型名
+
+    【since】：起始时间，为零值时获取全部收藏
+
+    【client】：http.Client对象
+
+  - @return 返回一个[]UserSubjectCollection和一个err。
+*/
+func collectionsUpdatedSince(userName, subjectTypeName string, since time.Time, client *http.Client) ([]UserSubjectCollection, error) {
+	const pageSize = 50
+	var collections []UserSubjectCollection
+	for offset := 0; ; {
+		jsonData, err := SearchCollectionsByUserName(userName, subjectTypeName, "", strconv.Itoa(pageSize), strconv.Itoa(offset), client)
+		if err != nil {
+			return nil, err
+		}
+		var page PagedUserCollection
+		if err = json.Unmarshal(jsonData, &page); err != nil {
+			errMsg := errors.New("Sync：解析返回体失败")
+			return nil, errMsg
+		}
+		for _, c := range page.Data {
+			// 更新时间相同的收藏可能是上次同步之后修改的，因此也会被获取
+			if !since.IsZero() && c.UpdatedAt.Before(since) {
+				return collections, nil
+			}
+			collections = append(collections, c)
+		}
+		offset += len(page.Data)
+		if offset >= page.Total {
+			return collections, nil
+		}
+		// 全量同步会删除没有获取到的收藏，不能把不完整的结果当作全部收藏
+		if len(page.Data) == 0 {
+			errMsg := errors.New("Sync：返回的收藏数少于total")
+			return nil, errMsg
+		}
+	}
+}
+
+/*
+Sync
+
+  - @brief 与服务器同步。增量同步时按收藏的更新时间只获取上一次同步之后变化的条目收藏，全量同步时获取全部条目收藏并删除服务器上已经不存在的收藏。
+    第一次同步总是全量同步。变化的动漫、三次元条目会重新获取章节收藏（SyncEpisodes为true时），角色和人物收藏每次全部获取。
+    章节收藏使用有限的worker并发获取。同步完成后写入镜像目录。
+
+    API：/v0/users/{username}/collections、/v0/users/-/collections/{subject_id}/episodes、
+    /v0/users/{username}/collections/-/characters、/v0/users/{username}/collections/-/persons
+
+  - @param
+
+    【ctx】：context，会绑定到所有请求上，取消后正在进行的请求会中止。
+
+    【full】：是否全量同步。
+
+  - @return 返回一个*SyncReport和一个err。
+
+  - @retval 获取数据出错时本地镜像不会被修改。写入镜像目录出错时，内存中的镜像已经更新，report有效，
+    目录中的文件可能只写入了一部分，元数据最后写入，下一次同步会重新写入全部文件。
+*/
+func (m *Mirror) Sync(ctx context.Context, full bool) (*SyncReport, error) {
+	m.mu.RLock()
+	watermarks := make(map[string]time.Time, len(m.meta.Watermarks))
+	for k, v := range m.meta.Watermarks {
+		watermarks[k] = v
+	}
+	m.mu.RUnlock()
+
+	report := &SyncReport{Full: full, SyncedAt: time.Now()}
+	client := clientWithContext(ctx, m.opts.Client)
+	online := &OnlineCollections{UserName: m.opts.UserName, Client: client}
+
+	type typeResult struct {
+		full        bool
+		collections []UserSubjectCollection
+	}
+	results := make(map[int]typeResult, len(m.opts.SubjectTypes))
+	for _, name := range m.opts.SubjectTypes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		subjectType := SubjectTypeID(name)
+		since, ok := watermarks[strconv.Itoa(subjectType)]
+		typeFull := full || !ok
+		if typeFull {
+			since = time.Time{}
+		}
+		collections, err := collectionsUpdatedSince(m.opts.UserName, name, since, client)
+		if err != nil {
+			return nil, err
+		}
+		results[subjectType] = typeResult{full: typeFull, collections: collections}
+	}
+
+	// 需要同步章节收藏的条目
+	episodes := make(map[int][]UserEpisodeCollection)
+	if m.opts.SyncEpisodes {
+		var subjectIDs []int
+		for subjectType, result := range results {
+			if subjectType != 2 && subjectType != 6 {
+				continue
+			}
+			for _, c := range result.collections {
+				subjectIDs = append(subjectIDs, c.SubjectID)
+			}
+		}
+		lists := make([][]UserEpisodeCollection, len(subjectIDs))
+		errs := make([]error, len(subjectIDs))
+		started := bulkDo(ctx, len(subjectIDs), &BulkOptions{Concurrency: m.opts.Concurrency, Client: client}, func(i int, client *http.Client) {
+			worker := &OnlineCollections{UserName: m.opts.UserName, Client: client}
+			lists[i], errs[i] = worker.EpisodeCollections(subjectIDs[i])
+		})
+		for i, id := range subjectIDs {
+			if !started[i] {
+				return nil, ctx.Err()
+			}
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			episodes[id] = lists[i]
+		}
+	}
+
+	characters, err := online.CharacterCollections()
+	if err != nil {
+		return nil, err
+	}
+	persons, err := online.PersonCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for subjectType, result := range results {
+		key := strconv.Itoa(subjectType)
+		if result.full {
+			report.Full = true
+			fresh := collectionMap(result.collections)
+			for id, c := range m.subjects {
+				if _, ok := fresh[id]; !ok && c.SubjectType == subjectType {
+					delete(m.subjects, id)
+					delete(m.episodes, id)
+					report.Removed++
+				}
+			}
+			delete(m.meta.Watermarks, key)
+		}
+		watermark := m.meta.Watermarks[key]
+		for _, c := range result.collections {
+			m.subjects[c.SubjectID] = c
+			if c.UpdatedAt.After(watermark) {
+				watermark = c.UpdatedAt
+			}
+		}
+		report.Updated += len(result.collections)
+		m.meta.Watermarks[key] = watermark
+	}
+	for id, list := range episodes {
+		m.episodes[id] = list
+	}
+	report.Episodes = len(episodes)
+	m.characters = characters
+	m.persons = persons
+	report.Characters = len(characters)
+	report.Persons = len(persons)
+	m.meta.SyncedAt = report.SyncedAt
+
+	if err = m.save(); err != nil {
+		errMsg := errors.New("Sync：写入镜像失败")
+		return report, errMsg
+	}
+	return report, nil
+}
+
+/*
+ * @brief 写入镜像目录，调用时需要持有m.mu。元数据最后写入
+ */
+func (m *Mirror) save() error {
+	subjects := make([]UserSubjectCollection, 0, len(m.subjects))
+	for _, c := range m.subjects {
+		subjects = append(subjects, c)
+	}
+	sort.Slice(subjects, func(i, j int) bool { return subjects[i].SubjectID < subjects[j].SubjectID })
+	episodes := make(map[string][]UserEpisodeCollection, len(m.episodes))
+	for id, list := range m.episodes {
+		episodes[strconv.Itoa(id)] = list
+	}
+
+	files := []struct {
+		name string
+		v    interface{}
+	}{
+		{mirrorSubjectsFile, subjects},
+		{mirrorEpisodesFile, episodes},
+		{mirrorCharactersFile, m.characters},
+		{mirrorPersonsFile, m.persons},
+		{mirrorMetaFile, m.meta},
+	}
+	for _, f := range files {
+		if err := writeJSONFile(filepath.Join(m.opts.Dir, f.name), f.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Collections
+
+  - @brief 从镜像读取某个条目类型、某个收藏类型下的全部收藏，按更新时间从新到旧排序。
+
+  - @param
+
+    【subjectTypeName】：条目类型（只能是以下字符串：书籍、动漫、音乐、游戏、三次元。如果不满足以上字符串，则会返回错误）
+
+    【typeName】：收藏类型（想看、看过、在看、搁置、抛弃），为其他字符串时获取全部收藏类型。
+
+  - @return 返回一个[]UserSubjectCollection和一个err。
+*/
+func (m *Mirror) Collections(subjectTypeName, typeName string) ([]UserSubjectCollection, error) {
+	subjectType := SubjectTypeID(subjectTypeName)
+	if subjectType == 0 {
+		errMsg := errors.New("不匹配的subjectTypeName")
+		return nil, errMsg
+	}
+	collectionType := CollectionTypeID(typeName)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var collections []UserSubjectCollection
+	for _, c := range m.subjects {
+		if c.SubjectType == subjectType && (collectionType == 0 || c.Type == collectionType) {
+			collections = append(collections, c)
+		}
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		if !collections[i].UpdatedAt.Equal(collections[j].UpdatedAt) {
+			return collections[i].UpdatedAt.After(collections[j].UpdatedAt)
+		}
+		return collections[i].SubjectID < collections[j].SubjectID
+	})
+	return collections, nil
+}
+
+/*
+Collection
+
+  - @brief 从镜像读取单个条目的收藏。
+
+  - @param
+
+    【subjectID】：条目ID。
+
+  - @return 返回一个*UserSubjectCollection和一个err，镜像中没有时返回错误。
+*/
+func (m *Mirror) Collection(subjectID int) (*UserSubjectCollection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.subjects[subjectID]
+	if !ok {
+		errMsg := errors.New("Mirror：没有找到收藏")
+		return nil, errMsg
+	}
+	return &c, nil
+}
+
+/*
+EpisodeCollections
+
+  - @brief 从镜像读取条目中本篇的章节收藏。
+
+  - @param
+
+    【subjectID】：条目ID。
+
+  - @return 返回一个[]UserEpisodeCollection和一个err，没有同步过该条目的章节收藏时返回错误。
+*/
+func (m *Mirror) EpisodeCollections(subjectID int) ([]UserEpisodeCollection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list, ok := m.episodes[subjectID]
+	if !ok {
+		errMsg := errors.New("Mirror：没有同步章节收藏")
+		return nil, errMsg
+	}
+	return append([]UserEpisodeCollection(nil), list...), nil
+}
+
+/*
+CharacterCollections
+
+  - @brief 从镜像读取角色收藏。
+
+  - @return 返回一个[]UserCharacterCollection和一个err。
+*/
+func (m *Mirror) CharacterCollections() ([]UserCharacterCollection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]UserCharacterCollection(nil), m.characters...), nil
+}
+
+/*
+PersonCollections
+
+  - @brief 从镜像读取人物收藏。
+
+  - @return 返回一个[]UserPersonCollection和一个err。
+*/
+func (m *Mirror) PersonCollections() ([]UserPersonCollection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]UserPersonCollection(nil), m.persons...), nil
+}
+
+var (
+	_ CollectionReader = (*OnlineCollections)(nil)
+	_ CollectionReader = (*Mirror)(nil)
+)
@@ -0,0 +1,173 @@
+package lite_bangumi_api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+/*
+ * @brief 测试用的收藏服务器，anime为动漫条目收藏的返回体，可以在两次同步之间修改
+ */
+type mirrorServer struct {
+	mu    sync.Mutex
+	anime string
+}
+
+func (s *mirrorServer) setAnime(body string) {
+	s.mu.Lock()
+	s.anime = body
+	s.mu.Unlock()
+}
+
+func (s *mirrorServer) handle(req *http.Request) (int, string) {
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/v0/users/sai/collections/-/"):
+		return http.StatusOK, `{"total":0,"data":[]}`
+	case req.URL.Path == "/v0/users/sai/collections":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return http.StatusOK, s.anime
+	case strings.HasPrefix(req.URL.Path, "/v0/users/-/collections/") && strings.HasSuffix(req.URL.Path, "/episodes"):
+		return http.StatusOK, `{"total":1,"data":[{"episode":{"id":100},"type":2}]}`
+	}
+	return http.StatusNotFound, `{}`
+}
+
+func openTestMirror(t *testing.T, dir, userName string) *Mirror {
+	t.Helper()
+	m, err := OpenMirror(MirrorOptions{Dir: dir, UserName: userName, SubjectTypes: []string{"动漫"}, SyncEpisodes: true, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMirrorIncrementalSyncStopsAtWatermark(t *testing.T) {
+	server := &mirrorServer{anime: `{"total":2,"data":[
+		{"subject_id":1,"subject_type":2,"type":3,"updated_at":"2026-10-02T00:00:00Z"},
+		{"subject_id":2,"subject_type":2,"type":3,"updated_at":"2026-10-01T00:00:00Z"}]}`}
+	api := newMockAPI(t, server.handle)
+	m := openTestMirror(t, t.TempDir(), "sai")
+
+	report, err := m.Sync(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Full || report.Updated != 2 || report.Episodes != 2 {
+		t.Errorf("first sync = %+v, want full with 2 updated and 2 episodes", report)
+	}
+
+	// total大于一页，但第一页中已经出现早于水位线的收藏，不会再翻页
+	server.setAnime(`{"total":60,"data":[
+		{"subject_id":3,"subject_type":2,"type":1,"updated_at":"2026-10-03T00:00:00Z"},
+		{"subject_id":1,"subject_type":2,"type":2,"updated_at":"2026-10-02T00:00:00Z"},
+		{"subject_id":2,"subject_type":2,"type":3,"updated_at":"2026-10-01T00:00:00Z"}]}`)
+	report, err = m.Sync(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Full || report.Updated != 2 || report.Episodes != 2 {
+		t.Errorf("incremental sync = %+v, want 2 updated and 2 episodes", report)
+	}
+	if n := api.count("GET /v0/users/sai/collections?limit=50&offset=50"); n != 0 {
+		t.Errorf("second page requested %d times, want 0", n)
+	}
+	if n := api.count("GET /v0/users/sai/collections?limit=50&offset=0"); n != 2 {
+		t.Errorf("first page requested %d times, want 2", n)
+	}
+	if n := api.count("GET /v0/users/-/collections/2/"); n != 1 {
+		t.Errorf("episodes of unchanged subject requested %d times, want 1", n)
+	}
+	if c, err := m.Collection(1); err != nil || c.Type != 2 {
+		t.Errorf("Collection(1) = %+v, %v, want type 2", c, err)
+	}
+	if _, err = m.Collection(3); err != nil {
+		t.Errorf("Collection(3): %v", err)
+	}
+}
+
+func TestMirrorFullSyncRemovesDeleted(t *testing.T) {
+	server := &mirrorServer{anime: `{"total":2,"data":[
+		{"subject_id":1,"subject_type":2,"type":3,"updated_at":"2026-10-02T00:00:00Z"},
+		{"subject_id":2,"subject_type":2,"type":3,"updated_at":"2026-10-01T00:00:00Z"}]}`}
+	newMockAPI(t, server.handle)
+	m := openTestMirror(t, t.TempDir(), "sai")
+	if _, err := m.Sync(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	server.setAnime(`{"total":1,"data":[
+		{"subject_id":1,"subject_type":2,"type":3,"updated_at":"2026-10-02T00:00:00Z"}]}`)
+	report, err := m.Sync(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Removed != 0 {
+		t.Errorf("incremental sync removed %d, want 0", report.Removed)
+	}
+
+	report, err = m.Sync(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Full || report.Removed != 1 {
+		t.Errorf("full sync = %+v, want 1 removed", report)
+	}
+	if _, err = m.Collection(2); err == nil {
+		t.Error("Collection(2) err = nil, want the deleted collection to be removed")
+	}
+	if _, err = m.EpisodeCollections(2); err == nil {
+		t.Error("EpisodeCollections(2) err = nil, want the deleted episodes to be removed")
+	}
+}
+
+func TestMirrorReopen(t *testing.T) {
+	server := &mirrorServer{anime: `{"total":1,"data":[
+		{"subject_id":1,"subject_type":2,"type":3,"updated_at":"2026-10-02T00:00:00Z"}]}`}
+	dir := t.TempDir()
+	api := newMockAPI(t, server.handle)
+	if _, err := openTestMirror(t, dir, "sai").Sync(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenMirror(MirrorOptions{Dir: dir, UserName: "other"}); err == nil || !strings.Contains(err.Error(), "其他用户") {
+		t.Errorf("OpenMirror by another user: err = %v, want 镜像属于其他用户", err)
+	}
+
+	// 重新打开后离线读取，不发送请求
+	requests := api.count("GET")
+	m := openTestMirror(t, dir, "sai")
+	collections, err := m.Collections("动漫", "在看")
+	if err != nil || len(collections) != 1 || collections[0].SubjectID != 1 {
+		t.Errorf("Collections = %+v, %v, want subject 1", collections, err)
+	}
+	if list, err := m.EpisodeCollections(1); err != nil || len(list) != 1 {
+		t.Errorf("EpisodeCollections(1) = %+v, %v, want 1 episode", list, err)
+	}
+	if m.LastSync().IsZero() {
+		t.Error("LastSync is zero after reopening")
+	}
+	if n := api.count("GET"); n != requests {
+		t.Errorf("reopened mirror sent %d requests, want 0", n-requests)
+	}
+}
+
+func TestMirrorSyncCancelled(t *testing.T) {
+	api := newMockAPI(t, (&mirrorServer{anime: `{"total":0,"data":[]}`}).handle)
+	m := openTestMirror(t, t.TempDir(), "sai")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := m.Sync(ctx, false); err == nil {
+		t.Fatal("err = nil, want an error for the cancelled sync")
+	}
+	if n := api.count("GET"); n != 0 {
+		t.Errorf("requests = %d, want 0", n)
+	}
+	if !m.LastSync().IsZero() {
+		t.Error("cancelled sync modified the mirror")
+	}
+}
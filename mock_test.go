@@ -0,0 +1,52 @@
+package lite_bangumi_api
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+/*
+ * @brief 测试用的API：通过拦截器返回handler的结果，不发送真实请求，测试结束后恢复拦截器
+ */
+type mockAPI struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func newMockAPI(t *testing.T, handler func(req *http.Request) (int, string)) *mockAPI {
+	t.Helper()
+	m := &mockAPI{}
+	saved := Interceptors
+	Interceptors = []Interceptor{func(op string, req *http.Request, next RequestHandler) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		m.mu.Lock()
+		m.requests = append(m.requests, req.Method+" "+req.URL.RequestURI())
+		m.mu.Unlock()
+		status, body := handler(req)
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}}
+	t.Cleanup(func() { Interceptors = saved })
+	return m
+}
+
+func (m *mockAPI) count(prefix string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, r := range m.requests {
+		if strings.HasPrefix(r, prefix) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,232 @@
+/**
+ * @file 	model_calendar.go
+ * @brief 	calendar相关的数据结构以及放送时间表
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+)
+
+/*
+ * @brief Bangumi的星期以日本时间为准
+ */
+var japanLocation = time.FixedZone("JST", 9*60*60)
+
+/*
+ * @brief 星期，ID为1～7，分别表示星期一～星期日
+ */
+type CalendarWeekday struct {
+	EN string `json:"en"`
+	CN string `json:"cn"`
+	JA string `json:"ja"`
+	ID int    `json:"id"`
+}
+
+/*
+Weekday
+
+  - @brief 转为time.Weekday。
+
+  - @return 返回time.Weekday，ID为7时返回time.Sunday。
+*/
+func (w CalendarWeekday) Weekday() time.Weekday {
+	return time.Weekday(w.ID % 7)
+}
+
+/*
+ * @brief 每日放送中的条目
+ */
+type CalendarItem struct {
+	ID         int                    `json:"id"`
+	URL        string                 `json:"url"`
+	Type       int                    `json:"type"`
+	Name       string                 `json:"name"`
+	NameCN     string                 `json:"name_cn"`
+	Summary    string                 `json:"summary"`
+	AirDate    string                 `json:"air_date"`
+	AirWeekday int                    `json:"air_weekday"`
+	Images     Images                 `json:"images"`
+	Eps        int                    `json:"eps"`
+	EpsCount   int                    `json:"eps_count"`
+	Rating     Rating                 `json:"rating"`
+	Rank       int                    `json:"rank"`
+	Collection SubjectCollectionCount `json:"collection"`
+}
+
+/*
+ * @brief 每日放送中的一天，GetCalender的返回体是[]CalendarDay
+ */
+type CalendarDay struct {
+	Weekday CalendarWeekday `json:"weekday"`
+	Items   []CalendarItem  `json:"items"`
+}
+
+/*
+GetCalendarDays
+
+  - @brief 获取每日放送，与GetCalender相同，但返回解析后的结果。
+
+    API：/calendar
+
+  - @param
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]CalendarDay和一个err。
+
+  - @retval []CalendarDay是每日放送，err表示错误。如果err为nil，则没有错误。
+*/
+func GetCalendarDays(client *http.Client) ([]CalendarDay, error) {
+	jsonData, err := GetCalender(client)
+	if err != nil {
+		return nil, err
+	}
+	var days []CalendarDay
+	if err = json.Unmarshal(jsonData, &days); err != nil {
+		errMsg := errors.New("GetCalendarDays：解析返回体失败")
+		return nil, errMsg
+	}
+	return days, nil
+}
+
+/*
+ScheduledItem
+
+  - @brief 某一天放送的条目。
+
+    【JapanDate】：日本时间的放送日（0点）。
+
+    【AirTime】：放送时刻，放送时刻未知时为零值。
+*/
+type ScheduledItem struct {
+	CalendarItem
+	JapanDate time.Time
+	AirTime   time.Time
+}
+
+/*
+AiringOn
+
+  - @brief 计算某个时区的某一天放送的条目。
+    Bangumi的星期以日本时间为准，其他时区的一天可能跨越日本时间的两天：
+    放送时刻已知的条目按放送时刻精确判断；放送时刻未知的条目，只要日本时间的放送日与这一天有重叠就会返回。
+
+  - @param
+
+    【days】：每日放送。
+
+    【date】：日期，只使用其在loc中的年月日。
+
+    【loc】：时区，为nil时为日本时间。
+
+    【airTimes】：条目的放送时刻（日本时间当天0点起的时长），键为条目ID，可以为nil。
+
+  - @return 返回按放送时间排列的[]ScheduledItem。
+*/
+func AiringOn(days []CalendarDay, date time.Time, loc *time.Location, airTimes map[int]time.Duration) []ScheduledItem {
+	if loc == nil {
+		loc = japanLocation
+	}
+	local := date.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1)
+
+	// 与这一天重叠的日本时间的日期
+	first := start.In(japanLocation)
+	first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, japanLocation)
+	var japanDates []time.Time
+	for d := first; d.Before(end); d = d.AddDate(0, 0, 1) {
+		japanDates = append(japanDates, d)
+	}
+	// 深夜番的放送时刻可能超过24点
+	japanDates = append([]time.Time{first.AddDate(0, 0, -1)}, japanDates...)
+
+	var result []ScheduledItem
+	for _, japanDate := range japanDates {
+		for _, day := range days {
+			for _, item := range day.Items {
+				weekday := day.Weekday.Weekday()
+				if item.AirWeekday != 0 {
+					weekday = time.Weekday(item.AirWeekday % 7)
+				}
+				if weekday != japanDate.Weekday() {
+					continue
+				}
+				scheduled := ScheduledItem{CalendarItem: item, JapanDate: japanDate}
+				if airTime, ok := airTimes[item.ID]; ok {
+					scheduled.AirTime = japanDate.Add(airTime)
+					if scheduled.AirTime.Before(start) || !scheduled.AirTime.Before(end) {
+						continue
+					}
+				} else if !japanDate.AddDate(0, 0, 1).After(start) || !japanDate.Before(end) {
+					continue
+				}
+				result = append(result, scheduled)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].AirTime, result[j].AirTime
+		if a.IsZero() {
+			a = result[i].JapanDate
+		}
+		if b.IsZero() {
+			b = result[j].JapanDate
+		}
+		return a.Before(b)
+	})
+	return result
+}
+
+/*
+AiringToday
+
+  - @brief 计算某个时区今天放送的条目，参见AiringOn。
+
+  - @param
+
+    【days】：每日放送。
+
+    【loc】：时区，为nil时为日本时间。
+
+    【airTimes】：条目的放送时刻，可以为nil。
+
+  - @return 返回按放送时间排列的[]ScheduledItem。
+*/
+func AiringToday(days []CalendarDay, loc *time.Location, airTimes map[int]time.Duration) []ScheduledItem {
+	return AiringOn(days, time.Now(), loc, airTimes)
+}
+
+/*
+AiringTomorrow
+
+  - @brief 计算某个时区明天放送的条目，参见AiringOn。
+
+  - @param
+
+    【days】：每日放送。
+
+    【loc】：时区，为nil时为日本时间。
+
+    【airTimes】：条目的放送时刻，可以为nil。
+
+  - @return 返回按放送时间排列的[]ScheduledItem。
+*/
+func AiringTomorrow(days []CalendarDay, loc *time.Location, airTimes map[int]time.Duration) []ScheduledItem {
+	if loc == nil {
+		loc = japanLocation
+	}
+	now := time.Now().In(loc)
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 12, 0, 0, 0, loc)
+	return AiringOn(days, tomorrow, loc, airTimes)
+}
@@ -0,0 +1,49 @@
+/**
+ * @file 	model_characters.go
+ * @brief 	characters相关的数据结构
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+/*
+ * @brief 角色、人物的统计
+ */
+type Stat struct {
+	Comments int `json:"comments"`
+	Collects int `json:"collects"`
+}
+
+/*
+ * @brief 角色，SearchCharactersById的返回体
+ */
+type Character struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Type      int     `json:"type"`
+	Images    Images  `json:"images"`
+	Summary   string  `json:"summary"`
+	Locked    bool    `json:"locked"`
+	Infobox   Infobox `json:"infobox"`
+	Gender    string  `json:"gender"`
+	BloodType int     `json:"blood_type"`
+	BirthYear int     `json:"birth_year"`
+	BirthMon  int     `json:"birth_mon"`
+	BirthDay  int     `json:"birth_day"`
+	Stat      Stat    `json:"stat"`
+	NSFW      bool    `json:"nsfw"`
+}
+
+/*
+ * @brief 条目的角色，SearchSubjectsCharactersById返回体中的一项。Relation为"主角"、"配角"、"客串"，Actors为声优
+ */
+type RelatedCharacter struct {
+	ID       int          `json:"id"`
+	Name     string       `json:"name"`
+	Type     int          `json:"type"`
+	Images   Images       `json:"images"`
+	Relation string       `json:"relation"`
+	Actors   []SlimPerson `json:"actors"`
+}
@@ -0,0 +1,213 @@
+/**
+ * @file 	model_collections.go
+ * @brief 	collections相关的数据结构
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import "time"
+
+/*
+ * @brief 条目类型名和收藏类型名，顺序与SearchCollectionsByUserName中的参数一致
+ */
+var (
+	SubjectTypeNames    = []string{"书籍", "动漫", "音乐", "游戏", "三次元"}
+	CollectionTypeNames = []string{"想看", "看过", "在看", "搁置", "抛弃"}
+)
+
+/*
+SubjectTypeName
+
+  - @brief 条目类型ID转为类型名。
+
+  - @param
+
+    【subjectType】：条目类型ID（1书籍、2动漫、3音乐、4游戏、6三次元）。
+
+  - @return 返回类型名，未知类型返回空字符串。
+*/
+func SubjectTypeName(subjectType int) string {
+	switch subjectType {
+	case 1:
+		return "书籍"
+	case 2:
+		return "动漫"
+	case 3:
+		return "音乐"
+	case 4:
+		return "游戏"
+	case 6:
+		return "三次元"
+	default:
+		return ""
+	}
+}
+
+/*
+CollectionTypeName
+
+  - @brief 收藏类型ID转为类型名。
+
+  - @param
+
+    【collectionType】：收藏类型ID（1想看、2看过、3在看、4搁置、5抛弃）。
+
+  - @return 返回类型名，未知类型返回空字符串。
+*/
+func CollectionTypeName(collectionType int) string {
+	if collectionType < 1 || collectionType > len(CollectionTypeNames) {
+		return ""
+	}
+	return CollectionTypeNames[collectionType-1]
+}
+
+/*
+SubjectTypeID
+
+  - @brief 条目类型名转为类型ID。
+
+  - @param
+
+    【name】：类型名（书籍、动漫、音乐、游戏、三次元）。
+
+  - @return 返回类型ID，未知类型返回0。
+*/
+func SubjectTypeID(name string) int {
+	for _, id := range []int{1, 2, 3, 4, 6} {
+		if SubjectTypeName(id) == name {
+			return id
+		}
+	}
+	return 0
+}
+
+/*
+CollectionTypeID
+
+  - @brief 收藏类型名转为类型ID。
+
+  - @param
+
+    【name】：类型名（想看、看过、在看、搁置、抛弃）。
+
+  - @return 返回类型ID，未知类型返回0。
+*/
+func CollectionTypeID(name string) int {
+	for i, n := range CollectionTypeNames {
+		if n == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+/*
+ * @brief 图片地址
+ */
+type Images struct {
+	Large  string `json:"large"`
+	Common string `json:"common"`
+	Medium string `json:"medium"`
+	Small  string `json:"small"`
+	Grid   string `json:"grid"`
+}
+
+/*
+ * @brief 标签
+ */
+type Tag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+/*
+ * @brief 收藏中的条目简要信息
+ */
+type SlimSubject struct {
+	ID              int     `json:"id"`
+	Type            int     `json:"type"`
+	Name            string  `json:"name"`
+	NameCN          string  `json:"name_cn"`
+	ShortSummary    string  `json:"short_summary"`
+	Date            string  `json:"date"`
+	Images          Images  `json:"images"`
+	Volumes         int     `json:"volumes"`
+	Eps             int     `json:"eps"`
+	CollectionTotal int     `json:"collection_total"`
+	Score           float64 `json:"score"`
+	Rank            int     `json:"rank"`
+	Tags            []Tag   `json:"tags"`
+}
+
+/*
+ * @brief 用户的条目收藏
+ */
+type UserSubjectCollection struct {
+	SubjectID   int         `json:"subject_id"`
+	SubjectType int         `json:"subject_type"`
+	Rate        int         `json:"rate"`
+	Type        int         `json:"type"`
+	Comment     string      `json:"comment"`
+	Tags        []string    `json:"tags"`
+	EpStatus    int         `json:"ep_status"`
+	VolStatus   int         `json:"vol_status"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Private     bool        `json:"private"`
+	Subject     SlimSubject `json:"subject"`
+}
+
+/*
+ * @brief SearchCollectionsByUserName的返回体
+ */
+type PagedUserCollection struct {
+	Total  int                     `json:"total"`
+	Limit  int                     `json:"limit"`
+	Offset int                     `json:"offset"`
+	Data   []UserSubjectCollection `json:"data"`
+}
+
+/*
+ * @brief 用户的角色收藏
+ */
+type UserCharacterCollection struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Type      int       `json:"type"`
+	Images    Images    `json:"images"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+/*
+ * @brief SearchCharactersCollectionsByUserName的返回体
+ */
+type PagedUserCharacterCollection struct {
+	Total  int                       `json:"total"`
+	Limit  int                       `json:"limit"`
+	Offset int                       `json:"offset"`
+	Data   []UserCharacterCollection `json:"data"`
+}
+
+/*
+ * @brief 用户的人物收藏
+ */
+type UserPersonCollection struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Type      int       `json:"type"`
+	Career    []string  `json:"career"`
+	Images    Images    `json:"images"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+/*
+ * @brief SearchPersonsCollectionsByUserName的返回体
+ */
+type PagedUserPersonCollection struct {
+	Total  int                    `json:"total"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+	Data   []UserPersonCollection `json:"data"`
+}
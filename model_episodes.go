@@ -0,0 +1,57 @@
+/**
+ * @file 	model_episodes.go
+ * @brief 	episodes相关的数据结构
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+/*
+ * @brief 章节
+ */
+type Episode struct {
+	ID              int     `json:"id"`
+	Type            int     `json:"type"`
+	Name            string  `json:"name"`
+	NameCN          string  `json:"name_cn"`
+	Sort            float64 `json:"sort"`
+	Ep              float64 `json:"ep"`
+	Airdate         string  `json:"airdate"`
+	Comment         int     `json:"comment"`
+	Duration        string  `json:"duration"`
+	Desc            string  `json:"desc"`
+	Disc            int     `json:"disc"`
+	DurationSeconds int     `json:"duration_seconds"`
+	SubjectID       int     `json:"subject_id"`
+}
+
+/*
+ * @brief SearchEpisodesByEpisodesName的返回体
+ */
+type PagedEpisode struct {
+	Total  int       `json:"total"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+	Data   []Episode `json:"data"`
+}
+
+/*
+ * @brief 用户的章节收藏。Type为0未收藏、1想看、2看过、3抛弃，UpdatedAt为Unix时间戳
+ */
+type UserEpisodeCollection struct {
+	Episode   Episode `json:"episode"`
+	Type      int     `json:"type"`
+	UpdatedAt int64   `json:"updated_at"`
+}
+
+/*
+ * @brief SearchUsersCollectionsEpisodesBySubjectsID的返回体
+ */
+type PagedUserEpisodeCollection struct {
+	Total  int                     `json:"total"`
+	Limit  int                     `json:"limit"`
+	Offset int                     `json:"offset"`
+	Data   []UserEpisodeCollection `json:"data"`
+}
@@ -0,0 +1,263 @@
+/**
+ * @file 	model_legacy.go
+ * @brief 	旧版API（/search/subject/{keywords}）的数据结构
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+/*
+ResponseGroup
+
+  - @brief 旧版API返回数据大小。
+*/
+type ResponseGroup string
+
+const (
+	ResponseGroupSmall  ResponseGroup = "small"
+	ResponseGroupMedium ResponseGroup = "medium"
+	ResponseGroupLarge  ResponseGroup = "large"
+)
+
+/*
+ * @brief 旧版API中的用户
+ */
+type LegacyUser struct {
+	ID       int    `json:"id"`
+	URL      string `json:"url"`
+	UserName string `json:"username"`
+	NickName string `json:"nickname"`
+	Avatar   Images `json:"avatar"`
+	Sign     string `json:"sign"`
+}
+
+/*
+ * @brief 旧版API中的人物（声优、制作人员）
+ */
+type LegacyMono struct {
+	ID       int             `json:"id"`
+	URL      string          `json:"url"`
+	Name     string          `json:"name"`
+	NameCN   string          `json:"name_cn"`
+	RoleName string          `json:"role_name"`
+	Images   Images          `json:"images"`
+	Comment  int             `json:"comment"`
+	Collects int             `json:"collects"`
+	Info     json.RawMessage `json:"info"`
+}
+
+/*
+ * @brief 旧版API中的角色
+ */
+type LegacyCharacter struct {
+	LegacyMono
+	Actors []LegacyMono `json:"actors"`
+}
+
+/*
+ * @brief 旧版API中的制作人员
+ */
+type LegacyStaff struct {
+	LegacyMono
+	Jobs []string `json:"jobs"`
+}
+
+/*
+ * @brief 旧版API中的章节
+ */
+type LegacyEpisode struct {
+	ID       int     `json:"id"`
+	URL      string  `json:"url"`
+	Type     int     `json:"type"`
+	Sort     float64 `json:"sort"`
+	Name     string  `json:"name"`
+	NameCN   string  `json:"name_cn"`
+	Duration string  `json:"duration"`
+	Airdate  string  `json:"airdate"`
+	Comment  int     `json:"comment"`
+	Desc     string  `json:"desc"`
+	Status   string  `json:"status"`
+}
+
+/*
+ * @brief 旧版API中的讨论
+ */
+type LegacyTopic struct {
+	ID        int        `json:"id"`
+	URL       string     `json:"url"`
+	Title     string     `json:"title"`
+	MainID    int        `json:"main_id"`
+	Timestamp int64      `json:"timestamp"`
+	LastPost  int64      `json:"lastpost"`
+	Replies   int        `json:"replies"`
+	User      LegacyUser `json:"user"`
+}
+
+/*
+ * @brief 旧版API中的日志
+ */
+type LegacyBlog struct {
+	ID        int        `json:"id"`
+	URL       string     `json:"url"`
+	Title     string     `json:"title"`
+	Summary   string     `json:"summary"`
+	Image     string     `json:"image"`
+	Replies   int        `json:"replies"`
+	Timestamp int64      `json:"timestamp"`
+	Dateline  string     `json:"dateline"`
+	User      LegacyUser `json:"user"`
+}
+
+/*
+ * @brief responseGroup为small时的条目
+ */
+type LegacySubjectSmall struct {
+	ID         int    `json:"id"`
+	URL        string `json:"url"`
+	Type       int    `json:"type"`
+	Name       string `json:"name"`
+	NameCN     string `json:"name_cn"`
+	Summary    string `json:"summary"`
+	AirDate    string `json:"air_date"`
+	AirWeekday int    `json:"air_weekday"`
+	Images     Images `json:"images"`
+}
+
+/*
+ * @brief responseGroup为medium时的条目
+ */
+type LegacySubjectMedium struct {
+	LegacySubjectSmall
+	Eps        int                    `json:"eps"`
+	EpsCount   int                    `json:"eps_count"`
+	Rating     Rating                 `json:"rating"`
+	Rank       int                    `json:"rank"`
+	Collection SubjectCollectionCount `json:"collection"`
+	Crt        []LegacyCharacter      `json:"crt"`
+	Staff      []LegacyStaff          `json:"staff"`
+}
+
+/*
+ * @brief responseGroup为large时的条目。large中eps为章节列表，话数见EpsCount
+ */
+type LegacySubjectLarge struct {
+	LegacySubjectMedium
+	Eps   []LegacyEpisode `json:"eps"`
+	Topic []LegacyTopic   `json:"topic"`
+	Blog  []LegacyBlog    `json:"blog"`
+}
+
+/*
+ * @brief SearchAllSubjectsByResponseGroup的返回体，T为LegacySubjectSmall、LegacySubjectMedium或LegacySubjectLarge
+ */
+type LegacySearchResult[T any] struct {
+	Results int `json:"results"`
+	List    []T `json:"list"`
+}
+
+/*
+searchLegacySubjects
+
+  - @brief 调用SearchAllSubjectsByResponseGroup并解析返回体。没有结果时返回空列表
+
+  - @param
+
+    【op】：操作名，用于错误信息
+
+    其他参数与SearchAllSubjectsByResponseGroup相同
+
+  - @return 返回一个*LegacySearchResult[T]和一个err。
+*/
+func searchLegacySubjects[T any](op, keyWord, typeName string, responseGroup ResponseGroup, start, nmaxResults string, client *http.Client) (*LegacySearchResult[T], error) {
+	jsonData, err := SearchAllSubjectsByResponseGroup(keyWord, typeName, responseGroup, start, nmaxResults, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// 没有结果时旧版API返回{"request": "...", "code": 404, "error": "Not Found"}
+	var result struct {
+		LegacySearchResult[T]
+		Code int `json:"code"`
+	}
+	if err = json.Unmarshal(jsonData, &result); err != nil {
+		errMsg := errors.New(op + "：解析返回体失败")
+		return nil, errMsg
+	}
+	if result.Code == http.StatusNotFound {
+		return &LegacySearchResult[T]{}, nil
+	}
+	return &result.LegacySearchResult, nil
+}
+
+/*
+SearchAllSubjectsByNameSmall
+
+  - @brief 与SearchAllSubjectsByName相同，responseGroup为small，返回解析后的结果。
+
+    API：/search/subject/{keywords}
+
+  - @param
+
+    【keyWord】：关键字。
+
+    【typeName】：条目类型（只能是以下字符串：书籍、动漫、音乐、游戏、三次元。如果typeName不满足以上字符串，则将全局搜索）
+
+    【start】：开始的条目
+
+    【nmaxResults】：每页最大数量
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*LegacySearchResult[LegacySubjectSmall]和一个err。
+
+  - @retval err表示错误。如果err为nil，则没有错误。
+*/
+func SearchAllSubjectsByNameSmall(keyWord, typeName, start, nmaxResults string, client *http.Client) (*LegacySearchResult[LegacySubjectSmall], error) {
+	return searchLegacySubjects[LegacySubjectSmall]("SearchAllSubjectsByNameSmall", keyWord, typeName, ResponseGroupSmall, start, nmaxResults, client)
+}
+
+/*
+SearchAllSubjectsByNameMedium
+
+  - @brief 与SearchAllSubjectsByName相同，responseGroup为medium，返回解析后的结果。
+
+    API：/search/subject/{keywords}
+
+  - @param
+
+    参数与SearchAllSubjectsByNameSmall相同。
+
+  - @return 返回一个*LegacySearchResult[LegacySubjectMedium]和一个err。
+
+  - @retval err表示错误。如果err为nil，则没有错误。
+*/
+func SearchAllSubjectsByNameMedium(keyWord, typeName, start, nmaxResults string, client *http.Client) (*LegacySearchResult[LegacySubjectMedium], error) {
+	return searchLegacySubjects[LegacySubjectMedium]("SearchAllSubjectsByNameMedium", keyWord, typeName, ResponseGroupMedium, start, nmaxResults, client)
+}
+
+/*
+SearchAllSubjectsByNameLarge
+
+  - @brief 与SearchAllSubjectsByName相同，responseGroup为large，返回解析后的结果。
+
+    API：/search/subject/{keywords}
+
+  - @param
+
+    参数与SearchAllSubjectsByNameSmall相同。
+
+  - @return 返回一个*LegacySearchResult[LegacySubjectLarge]和一个err。
+
+  - @retval err表示错误。如果err为nil，则没有错误。
+*/
+func SearchAllSubjectsByNameLarge(keyWord, typeName, start, nmaxResults string, client *http.Client) (*LegacySearchResult[LegacySubjectLarge], error) {
+	return searchLegacySubjects[LegacySubjectLarge]("SearchAllSubjectsByNameLarge", keyWord, typeName, ResponseGroupLarge, start, nmaxResults, client)
+}
@@ -0,0 +1,43 @@
+package lite_bangumi_api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSearchAllSubjectsByName(t *testing.T) {
+	api := newMockAPI(t, func(req *http.Request) (int, string) {
+		return http.StatusOK, `{"request":"","code":404,"error":"Not Found"}`
+	})
+
+	tests := []struct {
+		name          string
+		responseGroup string
+		wantErr       bool
+	}{
+		{"small", "small", false},
+		{"large", "large", false},
+		{"empty", "", true},
+		{"unknown", "huge", true},
+		{"uppercase", "Small", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SearchAllSubjectsByName("a", "动漫", tt.responseGroup, "0", "10", http.DefaultClient)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchAllSubjectsByName(%q) err = %v, wantErr %v", tt.responseGroup, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := SearchAllSubjectsByResponseGroup("a", "动漫", ResponseGroup("huge"), "0", "10", http.DefaultClient); err == nil {
+		t.Error("SearchAllSubjectsByResponseGroup(huge) err = nil, want an error")
+	}
+
+	result, err := SearchAllSubjectsByNameSmall("Fate/stay night?", "", "0", "10", http.DefaultClient)
+	if err != nil || result.Results != 0 || len(result.List) != 0 {
+		t.Errorf("no results = %+v, %v, want an empty result", result, err)
+	}
+	if n := api.count("GET /search/subject/Fate%2Fstay%20night%3F?"); n != 1 {
+		t.Errorf("escaped keyword requested %d times, want 1", n)
+	}
+}
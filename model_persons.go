@@ -0,0 +1,69 @@
+/**
+ * @file 	model_persons.go
+ * @brief 	persons相关的数据结构
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+/*
+ * @brief 人物，SearchPersonsById的返回体
+ */
+type Person struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	Type         int      `json:"type"`
+	Career       []string `json:"career"`
+	Images       Images   `json:"images"`
+	Summary      string   `json:"summary"`
+	Locked       bool     `json:"locked"`
+	LastModified string   `json:"last_modified"`
+	Infobox      Infobox  `json:"infobox"`
+	Gender       string   `json:"gender"`
+	BloodType    int      `json:"blood_type"`
+	BirthYear    int      `json:"birth_year"`
+	BirthMon     int      `json:"birth_mon"`
+	BirthDay     int      `json:"birth_day"`
+	Stat         Stat     `json:"stat"`
+}
+
+/*
+ * @brief 人物参与的条目，SearchPersonsSubjectsById返回体中的一项。Staff为职位，如"导演"
+ */
+type PersonRelatedSubject struct {
+	ID     int    `json:"id"`
+	Type   int    `json:"type"`
+	Staff  string `json:"staff"`
+	Eps    string `json:"eps"`
+	Name   string `json:"name"`
+	NameCN string `json:"name_cn"`
+	Image  string `json:"image"`
+}
+
+/*
+ * @brief 条目的相关人物，SearchSubjectsPersonsById返回体中的一项。Relation为职位，如"导演"、"原画"
+ */
+type RelatedPerson struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Type     int      `json:"type"`
+	Career   []string `json:"career"`
+	Images   Images   `json:"images"`
+	Relation string   `json:"relation"`
+	Eps      string   `json:"eps"`
+}
+
+/*
+ * @brief 简略的人物信息，如RelatedCharacter中的声优
+ */
+type SlimPerson struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	Type         int      `json:"type"`
+	Career       []string `json:"career"`
+	Images       Images   `json:"images"`
+	ShortSummary string   `json:"short_summary"`
+	Locked       bool     `json:"locked"`
+}
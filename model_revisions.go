@@ -0,0 +1,487 @@
+/**
+ * @file 	model_revisions.go
+ * @brief 	revisions相关的数据结构
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ * @brief 编辑者
+ */
+type Creator struct {
+	UserName string `json:"username"`
+	NickName string `json:"nickname"`
+}
+
+/*
+ * @brief 编辑历史摘要
+ */
+type Revision struct {
+	ID        int       `json:"id"`
+	Type      int       `json:"type"`
+	Creator   Creator   `json:"creator"`
+	Summary   string    `json:"summary"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+/*
+ * @brief Search*RevisionsById的返回体
+ */
+type PagedRevision struct {
+	Total  int        `json:"total"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+	Data   []Revision `json:"data"`
+}
+
+/*
+ * @brief 条目编辑历史的内容，infobox为wiki文本
+ */
+type SubjectRevisionData struct {
+	SubjectID int    `json:"subject_id"`
+	Name      string `json:"name"`
+	NameCN    string `json:"name_cn"`
+	Infobox   string `json:"field_infobox"`
+	Summary   string `json:"field_summary"`
+	FieldEps  int    `json:"field_eps"`
+	Platform  int    `json:"platform"`
+	Type      int    `json:"type"`
+	TypeID    int    `json:"type_id"`
+	VoteField string `json:"vote_field"`
+}
+
+/*
+ * @brief 条目编辑历史详细信息
+ */
+type SubjectRevision struct {
+	Revision
+	Data *SubjectRevisionData `json:"data"`
+}
+
+/*
+ * @brief 图片
+ */
+type RevisionExtra struct {
+	Img string `json:"img"`
+}
+
+/*
+ * @brief 人物编辑历史的内容，infobox为wiki文本
+ */
+type PersonRevisionData struct {
+	Name       string          `json:"prsn_name"`
+	Infobox    string          `json:"prsn_infobox"`
+	Summary    string          `json:"prsn_summary"`
+	Profession map[string]bool `json:"profession"`
+	Extra      RevisionExtra   `json:"extra"`
+}
+
+/*
+ * @brief 人物编辑历史详细信息，Data的键为人物ID
+ */
+type PersonRevision struct {
+	Revision
+	Data map[string]PersonRevisionData `json:"data"`
+}
+
+/*
+ * @brief 角色编辑历史的内容，infobox为wiki文本
+ */
+type CharacterRevisionData struct {
+	Name    string        `json:"name"`
+	Infobox string        `json:"infobox"`
+	Summary string        `json:"summary"`
+	Extra   RevisionExtra `json:"extra"`
+}
+
+/*
+ * @brief 角色编辑历史详细信息，Data的键为角色ID
+ */
+type CharacterRevision struct {
+	Revision
+	Data map[string]CharacterRevisionData `json:"data"`
+}
+
+/*
+ * @brief 章节编辑历史的内容
+ */
+type EpisodeRevisionData struct {
+	Airdate  string `json:"airdate"`
+	Desc     string `json:"desc"`
+	Duration string `json:"duration"`
+	Ep       string `json:"ep"`
+	Name     string `json:"name"`
+	NameCN   string `json:"name_cn"`
+	Type     string `json:"type"`
+}
+
+/*
+ * @brief 章节编辑历史详细信息，Data的键为章节ID
+ */
+type EpisodeRevision struct {
+	Revision
+	Data map[string]EpisodeRevisionData `json:"data"`
+}
+
+/*
+RevisionContent
+
+  - @brief 编辑历史中可以比较的内容。
+
+    【Name】【NameCN】【Summary】：名称、中文名、简介。
+
+    【Infobox】：infobox的wiki文本。
+
+    【Fields】：其他字段，如章节的airdate。
+*/
+type RevisionContent struct {
+	Name    string
+	NameCN  string
+	Summary string
+	Infobox string
+	Fields  map[string]string
+}
+
+/*
+Content
+
+  - @brief 获取条目编辑历史中可以比较的内容。
+
+  - @return 返回一个RevisionContent。
+*/
+func (r *SubjectRevision) Content() RevisionContent {
+	if r.Data == nil {
+		return RevisionContent{}
+	}
+	return RevisionContent{
+		Name:    r.Data.Name,
+		NameCN:  r.Data.NameCN,
+		Summary: r.Data.Summary,
+		Infobox: r.Data.Infobox,
+		Fields: map[string]string{
+			"platform":  strconv.Itoa(r.Data.Platform),
+			"field_eps": strconv.Itoa(r.Data.FieldEps),
+		},
+	}
+}
+
+/*
+Content
+
+  - @brief 获取人物编辑历史中可以比较的内容。一次编辑历史只包含一个人物。
+
+  - @return 返回一个RevisionContent。
+*/
+func (r *PersonRevision) Content() RevisionContent {
+	for _, data := range r.Data {
+		var professions []string
+		for name, ok := range data.Profession {
+			if ok {
+				professions = append(professions, name)
+			}
+		}
+		sort.Strings(professions)
+		return RevisionContent{
+			Name:    data.Name,
+			Summary: data.Summary,
+			Infobox: data.Infobox,
+			Fields: map[string]string{
+				"img":        data.Extra.Img,
+				"profession": strings.Join(professions, ","),
+			},
+		}
+	}
+	return RevisionContent{}
+}
+
+/*
+Content
+
+  - @brief 获取角色编辑历史中可以比较的内容。一次编辑历史只包含一个角色。
+
+  - @return 返回一个RevisionContent。
+*/
+func (r *CharacterRevision) Content() RevisionContent {
+	for _, data := range r.Data {
+		return RevisionContent{
+			Name:    data.Name,
+			Summary: data.Summary,
+			Infobox: data.Infobox,
+			Fields:  map[string]string{"img": data.Extra.Img},
+		}
+	}
+	return RevisionContent{}
+}
+
+/*
+Content
+
+  - @brief 获取章节编辑历史中可以比较的内容。一次编辑历史只包含一个章节。
+
+  - @return 返回一个RevisionContent。
+*/
+func (r *EpisodeRevision) Content() RevisionContent {
+	for _, data := range r.Data {
+		return RevisionContent{
+			Name:    data.Name,
+			NameCN:  data.NameCN,
+			Summary: data.Desc,
+			Fields: map[string]string{
+				"airdate":  data.Airdate,
+				"duration": data.Duration,
+				"ep":       data.Ep,
+				"type":     data.Type,
+			},
+		}
+	}
+	return RevisionContent{}
+}
+
+/*
+decodeRevisionJSON
+
+  - @brief 解析编辑历史的返回体
+
+  - @param
+
+    【op】：操作名，用于错误信息
+
+    【jsonData】：返回体
+
+    【err】：API函数返回的错误
+
+    【v】：解析结果
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func decodeRevisionJSON(op string, jsonData []byte, err error, v interface{}) error {
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(jsonData, v); err != nil {
+		errMsg := errors.New(op + "：解析返回体失败")
+		return errMsg
+	}
+	return nil
+}
+
+/*
+GetSubjectRevisions
+
+  - @brief 与SearchSubjectsRevisionsById相同，返回解析后的结果。
+
+    API：/v0/revisions/subjects
+
+  - @param
+
+    【subID】：条目ID
+
+    【limit】：当前页最大数
+
+    【offset】：起始位置
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*PagedRevision和一个err。
+*/
+func GetSubjectRevisions(subID, limit, offset string, client *http.Client) (*PagedRevision, error) {
+	var result PagedRevision
+	jsonData, err := SearchSubjectsRevisionsById(subID, limit, offset, client)
+	if err = decodeRevisionJSON("GetSubjectRevisions", jsonData, err, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+/*
+GetSubjectRevision
+
+  - @brief 与SearchSubjectsRevisionsByRevisionsId相同，返回解析后的结果。
+
+    API：/v0/revisions/subjects/{revision_id}
+
+  - @param
+
+    【revID】：历史ID
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*SubjectRevision和一个err。
+*/
+func GetSubjectRevision(revID string, client *http.Client) (*SubjectRevision, error) {
+	var result SubjectRevision
+	jsonData, err := SearchSubjectsRevisionsByRevisionsId(revID, client)
+	if err = decodeRevisionJSON("GetSubjectRevision", jsonData, err, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+/*
+GetPersonRevisions
+
+  - @brief 与SearchPersonsRevisionsById相同，返回解析后的结果。
+
+    API：/v0/revisions/persons
+
+  - @param
+
+    【perID】：人物ID
+
+    【limit】：当前页最大数
+
+    【offset】：起始位置
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*PagedRevision和一个err。
+*/
+func GetPersonRevisions(perID, limit, offset string, client *http.Client) (*PagedRevision, error) {
+	var result PagedRevision
+	jsonData, err := SearchPersonsRevisionsById(perID, limit, offset, client)
+	if err = decodeRevisionJSON("GetPersonRevisions", jsonData, err, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+/*
+GetPersonRevision
+
+  - @brief 与SearchPersonsRevisionsByRevisionsId相同，返回解析后的结果。
+
+    API：/v0/revisions/persons/{revision_id}
+
+  - @param
+
+    【revID】：历史ID
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*PersonRevision和一个err。
+*/
+func GetPersonRevision(revID string, client *http.Client) (*PersonRevision, error) {
+	var result PersonRevision
+	jsonData, err := SearchPersonsRevisionsByRevisionsId(revID, client)
+	if err = decodeRevisionJSON("GetPersonRevision", jsonData, err, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+/*
+GetCharacterRevisions
+
+  - @brief 与SearchCharactersRevisionsById相同，返回解析后的结果。
+
+    API：/v0/revisions/characters
+
+  - @param
+
+    【chrID】：角色ID
+
+    【limit】：当前页最大数
+
+    【offset】：起始位置
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*PagedRevision和一个err。
+*/
+func GetCharacterRevisions(chrID, limit, offset string, client *http.Client) (*PagedRevision, error) {
+	var result PagedRevision
+	jsonData, err := SearchCharactersRevisionsById(chrID, limit, offset, client)
+	if err = decodeRevisionJSON("GetCharacterRevisions", jsonData, err, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+/*
+GetCharacterRevision
+
+  - @brief 与SearchCharactersRevisionsByRevisionsId相同，返回解析后的结果。
+
+    API：/v0/revisions/characters/{revision_id}
+
+  - @param
+
+    【revID】：历史ID
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*CharacterRevision和一个err。
+*/
+func GetCharacterRevision(revID string, client *http.Client) (*CharacterRevision, error) {
+	var result CharacterRevision
+	jsonData, err := SearchCharactersRevisionsByRevisionsId(revID, client)
+	if err = decodeRevisionJSON("GetCharacterRevision", jsonData, err, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+/*
+GetEpisodeRevisions
+
+  - @brief 与SearchEpisodesRevisionsById相同，返回解析后的结果。
+
+    API：/v0/revisions/episodes
+
+  - @param
+
+    【epiID】：章节ID
+
+    【limit】：当前页最大数
+
+    【offset】：起始位置
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*PagedRevision和一个err。
+*/
+func GetEpisodeRevisions(epiID, limit, offset string, client *http.Client) (*PagedRevision, error) {
+	var result PagedRevision
+	jsonData, err := SearchEpisodesRevisionsById(epiID, limit, offset, client)
+	if err = decodeRevisionJSON("GetEpisodeRevisions", jsonData, err, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+/*
+GetEpisodeRevision
+
+  - @brief 与SearchEpisodesRevisionsByRevisionsId相同，返回解析后的结果。
+
+    API：/v0/revisions/episodes/{revision_id}
+
+  - @param
+
+    【revID】：历史ID
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*EpisodeRevision和一个err。
+*/
+func GetEpisodeRevision(revID string, client *http.Client) (*EpisodeRevision, error) {
+	var result EpisodeRevision
+	jsonData, err := SearchEpisodesRevisionsByRevisionsId(revID, client)
+	if err = decodeRevisionJSON("GetEpisodeRevision", jsonData, err, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
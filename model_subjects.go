@@ -0,0 +1,77 @@
+/**
+ * @file 	model_subjects.go
+ * @brief 	subjects相关的数据结构
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+/*
+ * @brief 评分
+ */
+type Rating struct {
+	Rank  int            `json:"rank"`
+	Total int            `json:"total"`
+	Count map[string]int `json:"count"`
+	Score float64        `json:"score"`
+}
+
+/*
+ * @brief 收藏人数
+ */
+type SubjectCollectionCount struct {
+	Wish    int `json:"wish"`
+	Collect int `json:"collect"`
+	Doing   int `json:"doing"`
+	OnHold  int `json:"on_hold"`
+	Dropped int `json:"dropped"`
+}
+
+/*
+ * @brief 条目，SearchSubjectsById的返回体以及SearchSubjectsByName返回体中的条目
+ */
+type Subject struct {
+	ID            int                    `json:"id"`
+	Type          int                    `json:"type"`
+	Name          string                 `json:"name"`
+	NameCN        string                 `json:"name_cn"`
+	Summary       string                 `json:"summary"`
+	NSFW          bool                   `json:"nsfw"`
+	Locked        bool                   `json:"locked"`
+	Date          string                 `json:"date"`
+	Platform      string                 `json:"platform"`
+	Images        Images                 `json:"images"`
+	Infobox       Infobox                `json:"infobox"`
+	Volumes       int                    `json:"volumes"`
+	Eps           int                    `json:"eps"`
+	TotalEpisodes int                    `json:"total_episodes"`
+	Rating        Rating                 `json:"rating"`
+	Collection    SubjectCollectionCount `json:"collection"`
+	Tags          []Tag                  `json:"tags"`
+	MetaTags      []string               `json:"meta_tags"`
+	Series        bool                   `json:"series"`
+}
+
+/*
+ * @brief SearchSubjectsByName的返回体
+ */
+type PagedSubject struct {
+	Total  int       `json:"total"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+	Data   []Subject `json:"data"`
+}
+
+/*
+ * @brief 相关条目，SearchSubjectsRelationsById返回体中的一项。Relation为关系，如"前传"、"续集"
+ */
+type RelatedSubject struct {
+	ID       int    `json:"id"`
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	NameCN   string `json:"name_cn"`
+	Images   Images `json:"images"`
+	Relation string `json:"relation"`
+}
@@ -0,0 +1,236 @@
+/**
+ * @file 	next_episode.go
+ * @brief 	获取在看条目的下一集
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+/*
+NextEpisodeOptions
+
+  - @brief 获取下一集的选项。
+
+    【UseProgress】：为true时按条目收藏的ep_status判断看过的集数（前ep_status个本篇），不获取章节收藏，不需要 access token。
+    为false时按章节收藏判断，看过和抛弃的章节都视为已处理，需要 access token。
+
+    【Now】：当前时间，为零值时使用当前时间。日本时间当天及以前放送的章节视为已放送。
+
+    【Concurrency】：最大并发数，小于等于0时为4。请求仍然会经过RateLimit限速。
+
+    【Client】：http.Client对象，为nil时使用http.DefaultClient。
+*/
+type NextEpisodeOptions struct {
+	UseProgress bool
+	Now         time.Time
+	Concurrency int
+	Client      *http.Client
+}
+
+/*
+NextEpisode
+
+  - @brief 一个在看条目的观看进度。
+
+    【Episode】：下一集，即最早的已放送但没有看过的本篇，追上进度时为nil。
+
+    【Backlog】：已放送但没有看过的本篇数。
+
+    【Watched】【Aired】【Total】：看过、已放送、全部的本篇数。
+
+    【NextAirdate】：追上进度时下一集的放送日期，没有时为空。
+
+    【UpdatedAt】：条目收藏的更新时间。
+
+    【Err】：获取章节失败时的错误。
+*/
+type NextEpisode struct {
+	Subject     SubjectRef `json:"subject"`
+	Episode     *Episode   `json:"episode"`
+	Backlog     int        `json:"backlog"`
+	Watched     int        `json:"watched"`
+	Aired       int        `json:"aired"`
+	Total       int        `json:"total"`
+	NextAirdate string     `json:"next_airdate"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Err         error      `json:"-"`
+}
+
+/*
+episodeAired
+
+  - @brief 章节是否已经放送
+
+  - @param
+
+    【e】：章节
+
+    【today】：日本时间的今天
+
+  - @return 返回一个bool，没有放送日期的章节视为未放送。
+*/
+func episodeAired(e Episode, today string) bool {
+	if _, err := time.Parse("2006-01-02", e.Airdate); err != nil {
+		return false
+	}
+	return e.Airdate <= today
+}
+
+/*
+ResolveNextEpisode
+
+  - @brief 获取一个在看条目的下一集。
+
+    API：/v0/episodes、/v0/users/-/collections/{subject_id}/episodes
+
+  - @param
+
+    【c】：条目收藏，可以由GetAllCollectionsByUserName获取。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回一个NextEpisode。错误记录在NextEpisode.Err中。
+*/
+func ResolveNextEpisode(c UserSubjectCollection, opts *NextEpisodeOptions) NextEpisode {
+	if opts == nil {
+		opts = &NextEpisodeOptions{}
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	today := now.In(japanLocation).Format("2006-01-02")
+
+	result := NextEpisode{
+		Subject:   SubjectRef{ID: c.SubjectID, Name: c.Subject.Name, NameCN: c.Subject.NameCN},
+		UpdatedAt: c.UpdatedAt,
+	}
+	subID := strconv.Itoa(c.SubjectID)
+	episodes, err := GetAllEpisodesBySubjectID(subID, "本篇", client)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	sort.SliceStable(episodes, func(i, j int) bool { return episodes[i].Sort < episodes[j].Sort })
+
+	watched := make(map[int]bool)
+	if opts.UseProgress {
+		for i := 0; i < c.EpStatus && i < len(episodes); i++ {
+			watched[episodes[i].ID] = true
+		}
+	} else {
+		collections, err := GetAllUserEpisodeCollections(subID, "本篇", client)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		for _, ec := range collections {
+			if ec.Type == 2 || ec.Type == 3 {
+				watched[ec.Episode.ID] = true
+			}
+		}
+	}
+
+	result.Total = len(episodes)
+	for i := range episodes {
+		e := episodes[i]
+		aired := episodeAired(e, today)
+		if aired {
+			result.Aired++
+		}
+		switch {
+		case watched[e.ID]:
+			result.Watched++
+		case aired:
+			result.Backlog++
+			if result.Episode == nil {
+				result.Episode = &e
+			}
+		case len(result.NextAirdate) == 0 && len(e.Airdate) != 0:
+			result.NextAirdate = e.Airdate
+		}
+	}
+	if result.Episode != nil {
+		result.NextAirdate = ""
+	}
+	return result
+}
+
+/*
+ResolveNextEpisodes
+
+  - @brief 获取用户全部在看的动漫、三次元条目的下一集。有下一集的条目排在前面，其余按收藏更新时间从新到旧排序。
+
+    API：/v0/users/{username}/collections、/v0/episodes、/v0/users/-/collections/{subject_id}/episodes
+
+  - @param
+
+    【ctx】：context，取消后获取收藏和正在进行的请求会中止，未开始的条目Err为ctx.Err()。
+
+    【userName】：用户名。不使用UseProgress时必须是access token对应的用户。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回一个[]NextEpisode和一个err。
+
+  - @retval 只有获取收藏失败时返回err，单个条目的错误记录在NextEpisode.Err中。
+*/
+func ResolveNextEpisodes(ctx context.Context, userName string, opts *NextEpisodeOptions) ([]NextEpisode, error) {
+	if opts == nil {
+		opts = &NextEpisodeOptions{}
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	// 取消后正在进行的请求也会中止
+	client = clientWithContext(ctx, client)
+
+	var collections []UserSubjectCollection
+	for _, subjectTypeName := range []string{"动漫", "三次元"} {
+		page, err := GetAllCollectionsByUserName(userName, subjectTypeName, "在看", client)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, page...)
+	}
+
+	results := make([]NextEpisode, len(collections))
+	started := bulkDo(ctx, len(collections), &BulkOptions{Concurrency: opts.Concurrency, Client: client}, func(i int, client *http.Client) {
+		resolveOpts := *opts
+		resolveOpts.Client = client
+		results[i] = ResolveNextEpisode(collections[i], &resolveOpts)
+	})
+	for i, c := range collections {
+		if !started[i] {
+			results[i] = NextEpisode{
+				Subject:   SubjectRef{ID: c.SubjectID, Name: c.Subject.Name, NameCN: c.Subject.NameCN},
+				UpdatedAt: c.UpdatedAt,
+				Err:       ctx.Err(),
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.Episode != nil) != (b.Episode != nil) {
+			return a.Episode != nil
+		}
+		return a.UpdatedAt.After(b.UpdatedAt)
+	})
+	return results, nil
+}
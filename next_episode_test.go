@@ -0,0 +1,127 @@
+package lite_bangumi_api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 2026-10-18 12:00 JST
+var nextEpisodeNow = time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)
+
+const nextEpisodeEpisodes = `{"total":4,"data":[
+	{"id":13,"sort":3,"airdate":"2026-10-18"},
+	{"id":11,"sort":1,"airdate":"2026-10-04"},
+	{"id":12,"sort":2,"airdate":"2026-10-11"},
+	{"id":14,"sort":4,"airdate":"2026-10-25"}]}`
+
+func TestEpisodeAired(t *testing.T) {
+	tests := []struct {
+		airdate string
+		want    bool
+	}{
+		{"2026-10-17", true},
+		{"2026-10-18", true},
+		{"2026-10-19", false},
+		{"", false},
+		{"2026-10", false},
+	}
+	for _, tt := range tests {
+		if got := episodeAired(Episode{Airdate: tt.airdate}, "2026-10-18"); got != tt.want {
+			t.Errorf("episodeAired(%q) = %v, want %v", tt.airdate, got, tt.want)
+		}
+	}
+}
+
+func TestResolveNextEpisode(t *testing.T) {
+	newMockAPI(t, func(req *http.Request) (int, string) {
+		switch {
+		case req.URL.Path == "/v0/episodes":
+			return http.StatusOK, nextEpisodeEpisodes
+		case strings.HasSuffix(req.URL.Path, "/episodes"):
+			// 第1集看过，第2集抛弃
+			return http.StatusOK, `{"total":2,"data":[{"episode":{"id":11},"type":2},{"episode":{"id":12},"type":3}]}`
+		}
+		return http.StatusNotFound, `{}`
+	})
+
+	tests := []struct {
+		name        string
+		epStatus    int
+		useProgress bool
+		wantEpisode int
+		wantBacklog int
+		wantWatched int
+		wantNext    string
+	}{
+		{"episode collections", 0, false, 13, 1, 2, ""},
+		{"progress behind", 1, true, 12, 2, 1, ""},
+		{"progress caught up", 3, true, 0, 0, 3, "2026-10-25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := UserSubjectCollection{SubjectID: 1, EpStatus: tt.epStatus}
+			got := ResolveNextEpisode(c, &NextEpisodeOptions{UseProgress: tt.useProgress, Now: nextEpisodeNow})
+			if got.Err != nil {
+				t.Fatal(got.Err)
+			}
+			gotEpisode := 0
+			if got.Episode != nil {
+				gotEpisode = got.Episode.ID
+			}
+			if gotEpisode != tt.wantEpisode || got.Backlog != tt.wantBacklog || got.Watched != tt.wantWatched || got.NextAirdate != tt.wantNext {
+				t.Errorf("got episode %d backlog %d watched %d next %q, want %d %d %d %q",
+					gotEpisode, got.Backlog, got.Watched, got.NextAirdate, tt.wantEpisode, tt.wantBacklog, tt.wantWatched, tt.wantNext)
+			}
+			if got.Aired != 3 || got.Total != 4 {
+				t.Errorf("aired %d total %d, want 3 4", got.Aired, got.Total)
+			}
+		})
+	}
+}
+
+func TestResolveNextEpisodesOrder(t *testing.T) {
+	newMockAPI(t, func(req *http.Request) (int, string) {
+		switch {
+		case strings.HasPrefix(req.URL.Path, "/v0/users/sai/collections") && req.URL.Query().Get("subject_type") == "2":
+			return http.StatusOK, `{"total":3,"data":[
+				{"subject_id":1,"ep_status":4,"updated_at":"2026-10-01T00:00:00Z"},
+				{"subject_id":2,"ep_status":0,"updated_at":"2026-09-01T00:00:00Z"},
+				{"subject_id":3,"ep_status":4,"updated_at":"2026-10-10T00:00:00Z"}]}`
+		case strings.HasPrefix(req.URL.Path, "/v0/users/sai/collections"):
+			return http.StatusOK, `{"total":0,"data":[]}`
+		case req.URL.Path == "/v0/episodes":
+			return http.StatusOK, nextEpisodeEpisodes
+		}
+		return http.StatusNotFound, `{}`
+	})
+
+	results, err := ResolveNextEpisodes(context.Background(), "sai", &NextEpisodeOptions{UseProgress: true, Now: nextEpisodeNow})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 有下一集的条目在前，其余按更新时间从新到旧
+	var order []int
+	for _, r := range results {
+		order = append(order, r.Subject.ID)
+	}
+	if want := []int{2, 3, 1}; len(order) != 3 || order[0] != want[0] || order[1] != want[1] || order[2] != want[2] {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestResolveNextEpisodesCancelled(t *testing.T) {
+	api := newMockAPI(t, func(req *http.Request) (int, string) { return http.StatusOK, `{"total":0,"data":[]}` })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ResolveNextEpisodes(ctx, "sai", &NextEpisodeOptions{UseProgress: true})
+	if err == nil {
+		t.Fatal("err = nil, want an error for the cancelled collection request")
+	}
+	if n := api.count("GET"); n != 0 {
+		t.Errorf("requests = %d, want 0", n)
+	}
+}
@@ -0,0 +1,403 @@
+/**
+ * @file 	notifier.go
+ * @brief 	定时检查在看条目的新章节并发出通知
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+ * @brief 默认检查间隔
+ */
+const defaultNotifierInterval = 30 * time.Minute
+
+/*
+EpisodeEvent
+
+  - @brief 新章节放送的通知。
+
+    【Subject】：条目。
+
+    【Episode】：新放送的章节。
+
+    【DetectedAt】：发现的时间。
+*/
+type EpisodeEvent struct {
+	Subject    SubjectRef `json:"subject"`
+	Episode    Episode    `json:"episode"`
+	DetectedAt time.Time  `json:"detected_at"`
+}
+
+/*
+NotifierOptions
+
+  - @brief Notifier的选项。
+
+    【UserName】：用户名，检查该用户在看的动画。
+
+    【Interval】：检查间隔，小于等于0时为30分钟。
+
+    【StatePath】：保存已通知章节和尚未送达的通知的文件路径，为空时不保存。重启后不会重复通知已送达的章节，尚未送达的通知会重新发出。
+
+    【AirTimes】：条目的放送时刻（日本时间当天0点起的时长），键为条目ID。有放送时刻的条目在放送时刻之后才通知，否则在放送日期当天通知。
+
+    【AnnounceExisting】：为false时，第一次检查到的条目只记录已经放送的章节而不通知；为true时全部通知。
+
+    【OnEvent】：通知回调。设置时通过回调通知，否则通过Events()返回的channel通知。
+
+    【OnError】：检查失败时的回调，可以为nil。
+
+    【Client】：http.Client对象，为nil时使用http.DefaultClient。
+*/
+type NotifierOptions struct {
+	UserName         string
+	Interval         time.Duration
+	StatePath        string
+	AirTimes         map[int]time.Duration
+	AnnounceExisting bool
+	OnEvent          func(EpisodeEvent)
+	OnError          func(error)
+	Client           *http.Client
+}
+
+/*
+ * @brief 保存到文件的状态，Seen的键为条目ID，值为已发现的章节ID，Pending为已发现但尚未送达的通知
+ */
+type notifierState struct {
+	Seen    map[string][]int `json:"seen"`
+	Pending []EpisodeEvent   `json:"pending,omitempty"`
+}
+
+/*
+Notifier
+
+  - @brief 定时检查每日放送和用户在看的动画，发现新放送的章节时发出EpisodeEvent。
+*/
+type Notifier struct {
+	opts   NotifierOptions
+	client *http.Client
+	events chan EpisodeEvent
+	now    func() time.Time
+
+	mu      sync.Mutex
+	seen    map[int]map[int]bool
+	pending []EpisodeEvent
+}
+
+/*
+NewNotifier
+
+  - @brief 创建Notifier，设置了StatePath时读取已发现的章节和尚未送达的通知。
+
+  - @param
+
+    【opts】：选项。
+
+  - @return 返回一个*Notifier和一个err。
+
+  - @retval *Notifier是Notifier，err表示错误。如果err为nil，则没有错误。
+*/
+func NewNotifier(opts NotifierOptions) (*Notifier, error) {
+	if len(opts.UserName) == 0 {
+		errMsg := errors.New("NewNotifier：缺少用户名")
+		return nil, errMsg
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultNotifierInterval
+	}
+	n := &Notifier{
+		opts:   opts,
+		client: opts.Client,
+		events: make(chan EpisodeEvent),
+		now:    time.Now,
+		seen:   make(map[int]map[int]bool),
+	}
+	if n.client == nil {
+		n.client = http.DefaultClient
+	}
+
+	if len(opts.StatePath) != 0 {
+		var state notifierState
+		if _, err := readJSONFile(opts.StatePath, &state); err != nil {
+			errMsg := errors.New("NewNotifier：读取状态文件失败")
+			return nil, errMsg
+		}
+		for key, ids := range state.Seen {
+			subjectID, err := strconv.Atoi(key)
+			if err != nil {
+				continue
+			}
+			n.seen[subjectID] = make(map[int]bool, len(ids))
+			for _, id := range ids {
+				n.seen[subjectID][id] = true
+			}
+		}
+		n.pending = state.Pending
+	}
+	return n, nil
+}
+
+/*
+Events
+
+  - @brief 获取通知的channel。没有设置OnEvent时通过该channel通知，Run返回时channel会被关闭。channel没有缓冲，通知被读取后才视为已送达。
+
+  - @return 返回一个<-chan EpisodeEvent。
+*/
+func (n *Notifier) Events() <-chan EpisodeEvent {
+	return n.events
+}
+
+/*
+Run
+
+  - @brief 立即检查一次，之后每隔Interval检查一次，直到ctx被取消。只能调用一次。
+    每个通知送达（写入channel或OnEvent返回）后才会从状态文件中移除，取消或进程退出时尚未送达的通知会在下次Run时重新发出。
+
+  - @param
+
+    【ctx】：context。
+
+  - @return 返回ctx.Err()。
+*/
+func (n *Notifier) Run(ctx context.Context) error {
+	defer close(n.events)
+	ticker := time.NewTicker(n.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := n.poll(ctx); err != nil && n.opts.OnError != nil {
+			n.opts.OnError(err)
+		}
+		for _, event := range n.pendingEvents() {
+			if n.opts.OnEvent != nil {
+				n.opts.OnEvent(event)
+			} else {
+				select {
+				case n.events <- event:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			if err := n.ack(event); err != nil && n.opts.OnError != nil {
+				n.opts.OnError(err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+/*
+Poll
+
+  - @brief 检查一次，返回新放送的章节（包括之前尚未送达的通知）并保存状态。返回的通知视为已送达。
+    Poll本身不会发出通知，Run会检查并发出通知。
+
+    API：/calendar、/v0/users/{username}/collections、/v0/episodes
+
+  - @param
+
+    【ctx】：context，会绑定到所有请求上，取消后正在进行的请求会中止。
+
+  - @return 返回一个[]EpisodeEvent和一个err。
+
+  - @retval 获取每日放送或收藏失败时返回err；单个条目获取章节失败时跳过该条目，返回已经发现的通知和最后一个错误。
+*/
+func (n *Notifier) Poll(ctx context.Context) ([]EpisodeEvent, error) {
+	pollErr := n.poll(ctx)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	events := n.pending
+	n.pending = nil
+	if err := n.saveState(); err != nil {
+		return events, err
+	}
+	return events, pollErr
+}
+
+/*
+poll
+
+  - @brief 检查一次，把新放送的章节加入尚未送达的通知并保存状态
+
+  - @param
+
+    【ctx】：context
+
+  - @return 返回一个err，含义与Poll相同。
+*/
+func (n *Notifier) poll(ctx context.Context) error {
+	client := clientWithContext(ctx, n.client)
+	days, err := GetCalendarDays(client)
+	if err != nil {
+		return err
+	}
+	airing := make(map[int]bool)
+	for _, day := range days {
+		for _, item := range day.Items {
+			airing[item.ID] = true
+		}
+	}
+
+	watching, err := GetAllCollectionsByUserName(n.opts.UserName, "动漫", "在看", client)
+	if err != nil {
+		return err
+	}
+
+	// 请求期间不持有n.mu，Run可以继续发出和确认通知
+	type subjectEpisodes struct {
+		collection UserSubjectCollection
+		episodes   []Episode
+	}
+	var fetched []subjectEpisodes
+	var lastErr error
+	watched := make(map[int]bool, len(watching))
+	for _, c := range watching {
+		watched[c.SubjectID] = true
+		if !airing[c.SubjectID] {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			lastErr = err
+			break
+		}
+		episodes, err := GetAllEpisodesBySubjectID(strconv.Itoa(c.SubjectID), "本篇", client)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		sort.SliceStable(episodes, func(i, j int) bool { return episodes[i].Sort < episodes[j].Sort })
+		fetched = append(fetched, subjectEpisodes{collection: c, episodes: episodes})
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	now := n.now()
+	for _, f := range fetched {
+		c := f.collection
+		seen, known := n.seen[c.SubjectID]
+		if !known {
+			seen = make(map[int]bool)
+			n.seen[c.SubjectID] = seen
+		}
+		for _, e := range f.episodes {
+			if seen[e.ID] || !n.episodeAired(c.SubjectID, e, now) {
+				continue
+			}
+			seen[e.ID] = true
+			if known || n.opts.AnnounceExisting {
+				n.pending = append(n.pending, EpisodeEvent{
+					Subject:    SubjectRef{ID: c.SubjectID, Name: c.Subject.Name, NameCN: c.Subject.NameCN},
+					Episode:    e,
+					DetectedAt: now,
+				})
+			}
+		}
+	}
+
+	// 不再在看的条目不需要保留，尚未送达的通知仍然保留
+	for subjectID := range n.seen {
+		if !watched[subjectID] {
+			delete(n.seen, subjectID)
+		}
+	}
+	if err = n.saveState(); err != nil {
+		return err
+	}
+	return lastErr
+}
+
+/*
+ * @brief 获取尚未送达的通知的副本
+ */
+func (n *Notifier) pendingEvents() []EpisodeEvent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]EpisodeEvent(nil), n.pending...)
+}
+
+/*
+ack
+
+  - @brief 通知送达后从尚未送达的通知中移除并保存状态
+
+  - @param
+
+    【event】：已送达的通知
+
+  - @return 返回一个err。
+*/
+func (n *Notifier) ack(event EpisodeEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i, e := range n.pending {
+		if e.Subject.ID == event.Subject.ID && e.Episode.ID == event.Episode.ID {
+			n.pending = append(n.pending[:i], n.pending[i+1:]...)
+			break
+		}
+	}
+	return n.saveState()
+}
+
+/*
+episodeAired
+
+  - @brief 章节是否已经放送。有放送时刻时以放送时刻为准，否则放送日期当天即视为已放送
+
+  - @param
+
+    【subjectID】：条目ID
+
+    【e】：章节
+
+    【now】：当前时间
+
+  - @return 返回一个bool。
+*/
+func (n *Notifier) episodeAired(subjectID int, e Episode, now time.Time) bool {
+	date, err := time.ParseInLocation("2006-01-02", e.Airdate, japanLocation)
+	if err != nil {
+		return false
+	}
+	if airTime, ok := n.opts.AirTimes[subjectID]; ok {
+		return !now.Before(date.Add(airTime))
+	}
+	return !now.Before(date)
+}
+
+/*
+ * @brief 保存状态，调用时需要持有n.mu
+ */
+func (n *Notifier) saveState() error {
+	if len(n.opts.StatePath) == 0 {
+		return nil
+	}
+	state := notifierState{Seen: make(map[string][]int, len(n.seen)), Pending: n.pending}
+	for subjectID, seen := range n.seen {
+		ids := make([]int, 0, len(seen))
+		for id := range seen {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		state.Seen[strconv.Itoa(subjectID)] = ids
+	}
+	return writeJSONFile(n.opts.StatePath, state)
+}
@@ -0,0 +1,145 @@
+package lite_bangumi_api
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+ * @brief 测试用的API：条目1正在放送且在看，第1集10月11日、第2集10月18日放送
+ */
+func notifierAPI(t *testing.T, onEpisodes func()) *mockAPI {
+	return newMockAPI(t, func(req *http.Request) (int, string) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/calendar"):
+			return http.StatusOK, `[{"weekday":{"id":7},"items":[{"id":1},{"id":2}]}]`
+		case req.URL.Path == "/v0/users/sai/collections":
+			return http.StatusOK, `{"total":1,"data":[{"subject_id":1,"subject_type":2,"type":3,"subject":{"name":"a"}}]}`
+		case req.URL.Path == "/v0/episodes":
+			if onEpisodes != nil {
+				onEpisodes()
+			}
+			return http.StatusOK, `{"total":2,"data":[
+				{"id":12,"sort":2,"airdate":"2026-10-18"},
+				{"id":11,"sort":1,"airdate":"2026-10-11"}]}`
+		}
+		return http.StatusNotFound, `{}`
+	})
+}
+
+func newTestNotifier(t *testing.T, opts NotifierOptions, now time.Time) *Notifier {
+	t.Helper()
+	opts.UserName = "sai"
+	n, err := NewNotifier(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.now = func() time.Time { return now }
+	return n
+}
+
+func eventEpisodeIDs(events []EpisodeEvent) []int {
+	var ids []int
+	for _, e := range events {
+		ids = append(ids, e.Episode.ID)
+	}
+	return ids
+}
+
+func TestNotifierPoll(t *testing.T) {
+	notifierAPI(t, nil)
+	// 2026-10-18 12:00 JST，第2集在23:00放送
+	before := time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)
+	after := before.Add(12 * time.Hour)
+	airTimes := map[int]time.Duration{1: 23 * time.Hour}
+
+	tests := []struct {
+		name             string
+		announceExisting bool
+		want             [][]int
+	}{
+		{"existing episodes recorded silently", false, [][]int{nil, {12}, nil}},
+		{"existing episodes announced", true, [][]int{{11}, {12}, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestNotifier(t, NotifierOptions{AirTimes: airTimes, AnnounceExisting: tt.announceExisting}, before)
+			for i, want := range tt.want {
+				if i == 1 {
+					n.now = func() time.Time { return after }
+				}
+				events, err := n.Poll(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := eventEpisodeIDs(events); len(got) != len(want) || (len(got) == 1 && got[0] != want[0]) {
+					t.Errorf("poll %d = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifierStateRedeliversPending(t *testing.T) {
+	notifierAPI(t, nil)
+	path := filepath.Join(t.TempDir(), "notifier.json")
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	// poll只加入尚未送达的通知，模拟送达之前进程退出
+	n := newTestNotifier(t, NotifierOptions{StatePath: path, AnnounceExisting: true}, now)
+	if err := n.poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	n = newTestNotifier(t, NotifierOptions{StatePath: path, AnnounceExisting: true}, now)
+	events, err := n.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eventEpisodeIDs(events); len(got) != 2 || got[0] != 11 || got[1] != 12 {
+		t.Errorf("redelivered = %v, want [11 12]", got)
+	}
+
+	// 已送达的章节不会重复通知
+	n = newTestNotifier(t, NotifierOptions{StatePath: path, AnnounceExisting: true}, now)
+	if events, err = n.Poll(context.Background()); err != nil || len(events) != 0 {
+		t.Errorf("after delivery = %v, %v, want none", eventEpisodeIDs(events), err)
+	}
+}
+
+func TestNotifierPollUnlockedDuringRequests(t *testing.T) {
+	var n *Notifier
+	locked := false
+	notifierAPI(t, func() {
+		if n.mu.TryLock() {
+			n.mu.Unlock()
+		} else {
+			locked = true
+		}
+	})
+	n = newTestNotifier(t, NotifierOptions{}, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC))
+	if _, err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if locked {
+		t.Error("n.mu was held while fetching episodes")
+	}
+}
+
+func TestNotifierPollCancelled(t *testing.T) {
+	api := notifierAPI(t, nil)
+	n := newTestNotifier(t, NotifierOptions{}, time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := n.Poll(ctx); err == nil {
+		t.Fatal("err = nil, want an error for the cancelled poll")
+	}
+	if c := api.count("GET"); c != 0 {
+		t.Errorf("requests = %d, want 0", c)
+	}
+}
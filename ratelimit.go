@@ -0,0 +1,77 @@
+/**
+ * @file 	ratelimit.go
+ * @brief 	请求限速
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+RateLimiter
+
+  - @brief 限速接口。每次发送请求前都会调用Wait，Wait返回后才会发送请求。
+*/
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+/*
+ * @brief 全局限速器，为nil时不限速
+ */
+var RateLimit RateLimiter
+
+/*
+ * @brief 固定间隔限速器
+ */
+type intervalLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+/*
+NewRateLimiter
+
+  - @brief 创建一个固定间隔的限速器，两次请求之间至少间隔interval。
+
+  - @param
+
+    【interval】：请求间隔，如time.Second/4表示每秒最多4次请求。
+
+  - @return 返回一个RateLimiter。
+*/
+func NewRateLimiter(interval time.Duration) RateLimiter {
+	return &intervalLimiter{interval: interval}
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	t := l.next
+	if t.Before(now) {
+		t = now
+	}
+	l.next = t.Add(l.interval)
+	l.mu.Unlock()
+
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,420 @@
+/**
+ * @file 	recommend.go
+ * @brief 	根据收藏的标签和评分推荐条目
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+/*
+ * @brief 默认忽略的标签，这些标签几乎所有条目都有，不能反映喜好
+ */
+var DefaultIgnoredTags = []string{"TV", "TVA", "日本", "动画", "日本动画", "漫画", "小说", "游戏"}
+
+/*
+ * @brief 表示日期的标签，如"2013"、"2013年4月"
+ */
+var dateTagPattern = regexp.MustCompile(`^\d{4}(年(\d{1,2}月)?)?$`)
+
+/*
+ * @brief 每个条目参与计算的标签数
+ */
+const tagsPerSubject = 10
+
+/*
+RecommendOptions
+
+  - @brief 推荐的选项。
+
+    【SubjectType】：条目类型ID，小于等于0时为2（动画）。
+
+    【SearchTags】：用于搜索候选条目的标签数（按喜好程度），小于等于0时为10。
+
+    【CandidatesPerTag】：每个标签搜索的候选条目数，小于等于0时为30。
+
+    【Top】：最多返回的推荐数，小于等于0时为20。
+
+    【IgnoredTags】：忽略的标签，为nil时为DefaultIgnoredTags。表示日期的标签总是被忽略。
+*/
+type RecommendOptions struct {
+	SubjectType      int
+	SearchTags       int
+	CandidatesPerTag int
+	Top              int
+	IgnoredTags      []string
+}
+
+/*
+ * @brief 标签的喜好程度，Weight为-1到1，负数表示不喜欢
+ */
+type TagWeight struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+/*
+Recommendation
+
+  - @brief 一个推荐。
+
+    【Score】：推荐分数，越大越推荐。
+
+    【Rating】：条目的评分。
+
+    【Tags】：推荐理由中的标签，即对分数贡献最大的标签。
+
+    【Reason】：推荐理由，如"因为你喜欢的标签：治愈、日常"。
+*/
+type Recommendation struct {
+	Subject SubjectRef `json:"subject"`
+	Score   float64    `json:"score"`
+	Rating  float64    `json:"rating"`
+	Tags    []string   `json:"tags"`
+	Reason  string     `json:"reason"`
+}
+
+/*
+ * @brief 处理后的选项
+ */
+type recommendConfig struct {
+	subjectType      int
+	searchTags       int
+	candidatesPerTag int
+	top              int
+	ignored          map[string]bool
+}
+
+func newRecommendConfig(opts *RecommendOptions) recommendConfig {
+	c := recommendConfig{subjectType: 2, searchTags: 10, candidatesPerTag: 30, top: 20}
+	ignored := DefaultIgnoredTags
+	if opts != nil {
+		if opts.SubjectType > 0 {
+			c.subjectType = opts.SubjectType
+		}
+		if opts.SearchTags > 0 {
+			c.searchTags = opts.SearchTags
+		}
+		if opts.CandidatesPerTag > 0 {
+			c.candidatesPerTag = opts.CandidatesPerTag
+		}
+		if opts.Top > 0 {
+			c.top = opts.Top
+		}
+		if opts.IgnoredTags != nil {
+			ignored = opts.IgnoredTags
+		}
+	}
+	c.ignored = make(map[string]bool, len(ignored))
+	for _, tag := range ignored {
+		c.ignored[tag] = true
+	}
+	return c
+}
+
+/*
+normalizedTags
+
+  - @brief 取条目中标记人数最多的标签，按标记人数换算为0到1的权重
+
+  - @param
+
+    【tags】：条目的标签
+
+    【ignored】：忽略的标签
+
+  - @return 返回以标签名为键的权重。
+*/
+func normalizedTags(tags []Tag, ignored map[string]bool) map[string]float64 {
+	sorted := make([]Tag, 0, len(tags))
+	for _, tag := range tags {
+		if !ignored[tag.Name] && !dateTagPattern.MatchString(tag.Name) && tag.Count > 0 {
+			sorted = append(sorted, tag)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Count > sorted[j].Count })
+	if len(sorted) > tagsPerSubject {
+		sorted = sorted[:tagsPerSubject]
+	}
+	weights := make(map[string]float64, len(sorted))
+	for _, tag := range sorted {
+		weights[tag.Name] = float64(tag.Count) / float64(sorted[0].Count)
+	}
+	return weights
+}
+
+/*
+collectionPreference
+
+  - @brief 收藏的喜好程度。有评分时以用户的平均分为基准，没有评分时按收藏类型估计
+
+  - @param
+
+    【c】：收藏
+
+    【meanRating】：用户的平均分
+
+  - @return 返回喜好程度。
+*/
+func collectionPreference(c UserSubjectCollection, meanRating float64) float64 {
+	if c.Rate > 0 {
+		return (float64(c.Rate)-meanRating)/2 + 0.5
+	}
+	switch CollectionTypeName(c.Type) {
+	case "想看":
+		return 0.3
+	case "看过", "在看":
+		return 0.5
+	case "抛弃":
+		return -1
+	default:
+		return 0
+	}
+}
+
+/*
+BuildTagProfile
+
+  - @brief 根据收藏的标签和评分计算标签的喜好程度。高分条目的标签权重为正，低分和抛弃的条目的标签权重为负。
+
+  - @param
+
+    【collections】：收藏。
+
+    【opts】：选项，可以为nil。只统计SubjectType类型的条目。
+
+  - @return 返回一个[]TagWeight，按权重从大到小排序。
+*/
+func BuildTagProfile(collections []UserSubjectCollection, opts *RecommendOptions) []TagWeight {
+	config := newRecommendConfig(opts)
+
+	ratingSum, ratingCount := 0, 0
+	for _, c := range collections {
+		if c.SubjectType == config.subjectType && c.Rate > 0 {
+			ratingSum += c.Rate
+			ratingCount++
+		}
+	}
+	meanRating := 7.0
+	if ratingCount != 0 {
+		meanRating = float64(ratingSum) / float64(ratingCount)
+	}
+
+	weights := make(map[string]float64)
+	for _, c := range collections {
+		if c.SubjectType != config.subjectType {
+			continue
+		}
+		preference := collectionPreference(c, meanRating)
+		for name, w := range normalizedTags(c.Subject.Tags, config.ignored) {
+			weights[name] += preference * w
+		}
+	}
+
+	maxWeight := 0.0
+	for _, w := range weights {
+		maxWeight = math.Max(maxWeight, math.Abs(w))
+	}
+	profile := make([]TagWeight, 0, len(weights))
+	for name, w := range weights {
+		if maxWeight > 0 {
+			w /= maxWeight
+		}
+		profile = append(profile, TagWeight{Name: name, Weight: w})
+	}
+	sort.Slice(profile, func(i, j int) bool {
+		if profile[i].Weight != profile[j].Weight {
+			return profile[i].Weight > profile[j].Weight
+		}
+		return profile[i].Name < profile[j].Name
+	})
+	return profile
+}
+
+/*
+scoreRecommendation
+
+  - @brief 计算候选条目的推荐分数：标签喜好程度的加权和，再按条目评分略微调整
+
+  - @param
+
+    【s】：候选条目
+
+    【profile】：以标签名为键的喜好程度
+
+    【ignored】：忽略的标签
+
+  - @return 返回一个Recommendation。
+*/
+func scoreRecommendation(s Subject, profile map[string]float64, ignored map[string]bool) Recommendation {
+	type contribution struct {
+		name  string
+		value float64
+	}
+	tags := normalizedTags(s.Tags, ignored)
+	var contributions []contribution
+	sum := 0.0
+	for name, w := range tags {
+		value := profile[name] * w
+		sum += value
+		if value > 0 {
+			contributions = append(contributions, contribution{name, value})
+		}
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		if contributions[i].value != contributions[j].value {
+			return contributions[i].value > contributions[j].value
+		}
+		return contributions[i].name < contributions[j].name
+	})
+
+	r := Recommendation{
+		Subject: SubjectRef{ID: s.ID, Name: s.Name, NameCN: s.NameCN},
+		Rating:  s.Rating.Score,
+	}
+	if len(tags) != 0 {
+		r.Score = sum / math.Sqrt(float64(len(tags)))
+	}
+	r.Score *= 0.8 + 0.2*s.Rating.Score/10
+	for i := 0; i < len(contributions) && i < 3; i++ {
+		r.Tags = append(r.Tags, contributions[i].name)
+	}
+	if len(r.Tags) != 0 {
+		r.Reason = "因为你喜欢的标签：" + strings.Join(r.Tags, "、")
+	}
+	return r
+}
+
+/*
+RecommendFromCollections
+
+  - @brief 根据收藏推荐条目。用喜好程度最高的标签搜索候选条目，排除已经收藏的条目后在本地计算分数。
+
+    API：/v0/search/subjects
+
+  - @param
+
+    【collections】：用户的收藏，可以由ExportCollections或GetAllCollectionsByUserName获取。
+
+    【opts】：选项，可以为nil。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]Recommendation和一个err。
+
+  - @retval []Recommendation按分数从大到小排序，err表示错误。只有全部搜索都失败时才返回错误。
+*/
+func RecommendFromCollections(collections []UserSubjectCollection, opts *RecommendOptions, client *http.Client) ([]Recommendation, error) {
+	config := newRecommendConfig(opts)
+	profile := BuildTagProfile(collections, opts)
+	weights := make(map[string]float64, len(profile))
+	for _, tw := range profile {
+		weights[tw.Name] = tw.Weight
+	}
+	collected := make(map[int]bool, len(collections))
+	for _, c := range collections {
+		collected[c.SubjectID] = true
+	}
+
+	candidates := make(map[int]Subject)
+	var lastErr error
+	searched := 0
+	for _, tw := range profile {
+		if searched == config.searchTags || tw.Weight <= 0 {
+			break
+		}
+		searched++
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"keyword": "",
+			"sort":    "rank",
+			"filter":  map[string]interface{}{"type": []int{config.subjectType}, "tag": []string{tw.Name}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		jsonData, err := SearchSubjectsByName(fmt.Sprint(config.candidatesPerTag), "0", string(requestBody), client)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		var page PagedSubject
+		if err = json.Unmarshal(jsonData, &page); err != nil {
+			lastErr = errors.New("RecommendFromCollections：解析搜索结果失败")
+			continue
+		}
+		for _, s := range page.Data {
+			if !collected[s.ID] {
+				candidates[s.ID] = s
+			}
+		}
+	}
+	if len(candidates) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	recommendations := make([]Recommendation, 0, len(candidates))
+	for _, s := range candidates {
+		if r := scoreRecommendation(s, weights, config.ignored); r.Score > 0 {
+			recommendations = append(recommendations, r)
+		}
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Subject.ID < recommendations[j].Subject.ID
+	})
+	if len(recommendations) > config.top {
+		recommendations = recommendations[:config.top]
+	}
+	return recommendations, nil
+}
+
+/*
+RecommendSubjects
+
+  - @brief 获取用户某个条目类型的全部收藏并推荐条目，相当于GetAllCollectionsByUserName后调用RecommendFromCollections。
+
+    API：/v0/users/{username}/collections、/v0/search/subjects
+
+  - @param
+
+    【userName】：用户名。
+
+    【opts】：选项，可以为nil。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个[]Recommendation和一个err。
+
+  - @retval []Recommendation按分数从大到小排序，err表示错误。如果err为nil，则没有错误。
+*/
+func RecommendSubjects(userName string, opts *RecommendOptions, client *http.Client) ([]Recommendation, error) {
+	config := newRecommendConfig(opts)
+	subjectTypeName := SubjectTypeName(config.subjectType)
+	if len(subjectTypeName) == 0 {
+		errMsg := errors.New("RecommendSubjects：不匹配的条目类型")
+		return nil, errMsg
+	}
+	var collections []UserSubjectCollection
+	for _, typeName := range CollectionTypeNames {
+		page, err := GetAllCollectionsByUserName(userName, subjectTypeName, typeName, client)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, page...)
+	}
+	return RecommendFromCollections(collections, opts, client)
+}
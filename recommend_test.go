@@ -0,0 +1,170 @@
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizedTags(t *testing.T) {
+	ignored := newRecommendConfig(nil).ignored
+	tests := []struct {
+		name string
+		tags []Tag
+		want map[string]float64
+	}{
+		{"weights by count", []Tag{{"日常", 5}, {"治愈", 10}}, map[string]float64{"治愈": 1, "日常": 0.5}},
+		{"ignored and date tags", []Tag{{"TV", 100}, {"2013年4月", 50}, {"2013", 40}, {"2013年", 30}, {"治愈", 10}, {"空", 0}}, map[string]float64{"治愈": 1}},
+		{"empty", nil, map[string]float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizedTags(tt.tags, ignored); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizedTags = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var many []Tag
+	for i := 0; i < tagsPerSubject+2; i++ {
+		many = append(many, Tag{Name: string(rune('a' + i)), Count: 100 - i})
+	}
+	if got := normalizedTags(many, ignored); len(got) != tagsPerSubject || got["a"] != 1 {
+		t.Errorf("normalizedTags kept %d tags, want %d", len(got), tagsPerSubject)
+	}
+}
+
+func TestCollectionPreference(t *testing.T) {
+	tests := []struct {
+		name string
+		c    UserSubjectCollection
+		want float64
+	}{
+		{"above mean", UserSubjectCollection{Type: 2, Rate: 9}, 1.5},
+		{"below mean", UserSubjectCollection{Type: 2, Rate: 5}, -0.5},
+		{"wish", UserSubjectCollection{Type: 1}, 0.3},
+		{"watched without rating", UserSubjectCollection{Type: 2}, 0.5},
+		{"dropped", UserSubjectCollection{Type: 5}, -1},
+		{"on hold", UserSubjectCollection{Type: 4}, 0},
+	}
+	for _, tt := range tests {
+		if got := collectionPreference(tt.c, 7); got != tt.want {
+			t.Errorf("%s: collectionPreference = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+/*
+ * @brief 测试用的收藏：喜欢治愈、日常，不喜欢热血，抛弃了后宫。书籍不参与动画的推荐
+ */
+var recommendCollections = []UserSubjectCollection{
+	{SubjectID: 1, SubjectType: 2, Type: 2, Rate: 9, Subject: SlimSubject{Tags: []Tag{{"治愈", 10}, {"日常", 10}}}},
+	{SubjectID: 2, SubjectType: 2, Type: 2, Rate: 5, Subject: SlimSubject{Tags: []Tag{{"热血", 10}}}},
+	{SubjectID: 3, SubjectType: 2, Type: 5, Subject: SlimSubject{Tags: []Tag{{"后宫", 10}, {"日常", 5}}}},
+	{SubjectID: 4, SubjectType: 1, Type: 2, Rate: 10, Subject: SlimSubject{Tags: []Tag{{"推理", 10}}}},
+}
+
+func TestBuildTagProfile(t *testing.T) {
+	profile := BuildTagProfile(recommendCollections, nil)
+	want := []TagWeight{{"治愈", 1}, {"日常", 2.0 / 3}, {"热血", -1.0 / 3}, {"后宫", -2.0 / 3}}
+	if len(profile) != len(want) {
+		t.Fatalf("profile = %v, want %v", profile, want)
+	}
+	for i := range want {
+		if profile[i].Name != want[i].Name || math.Abs(profile[i].Weight-want[i].Weight) > 1e-9 {
+			t.Errorf("profile[%d] = %v, want %v", i, profile[i], want[i])
+		}
+	}
+
+	if profile = BuildTagProfile(recommendCollections, &RecommendOptions{SubjectType: 1}); len(profile) != 1 || profile[0] != (TagWeight{"推理", 1}) {
+		t.Errorf("book profile = %v, want [推理 1]", profile)
+	}
+}
+
+func TestScoreRecommendation(t *testing.T) {
+	profile := map[string]float64{"治愈": 1, "日常": 0.5, "后宫": -1}
+	ignored := newRecommendConfig(nil).ignored
+	tests := []struct {
+		name       string
+		subject    Subject
+		wantScore  float64
+		wantReason string
+	}{
+		{"liked tags", Subject{Tags: []Tag{{"治愈", 10}, {"日常", 10}}, Rating: Rating{Score: 10}},
+			1.5 / math.Sqrt2, "因为你喜欢的标签：治愈、日常"},
+		{"unrated subject", Subject{Tags: []Tag{{"治愈", 10}}}, 0.8, "因为你喜欢的标签：治愈"},
+		{"disliked tag", Subject{Tags: []Tag{{"后宫", 10}}, Rating: Rating{Score: 10}}, -1, ""},
+		{"no tags", Subject{Rating: Rating{Score: 10}}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := scoreRecommendation(tt.subject, profile, ignored)
+			if math.Abs(r.Score-tt.wantScore) > 1e-9 || r.Reason != tt.wantReason {
+				t.Errorf("score %v reason %q, want %v %q", r.Score, r.Reason, tt.wantScore, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRecommendFromCollections(t *testing.T) {
+	candidates := map[string]string{
+		"治愈": `{"total":3,"data":[
+			{"id":1,"tags":[{"name":"治愈","count":10}]},
+			{"id":100,"tags":[{"name":"治愈","count":10}],"rating":{"score":8}},
+			{"id":101,"tags":[{"name":"后宫","count":10},{"name":"治愈","count":5}],"rating":{"score":8}}]}`,
+		"日常": `{"total":1,"data":[{"id":102,"tags":[{"name":"日常","count":10}],"rating":{"score":9}}]}`,
+	}
+	tests := []struct {
+		name         string
+		opts         *RecommendOptions
+		want         []int
+		wantSearches int
+	}{
+		// 已收藏的1和分数为负的101不推荐，权重为负的标签不搜索
+		{"default", nil, []int{100, 102}, 2},
+		{"top", &RecommendOptions{Top: 1}, []int{100}, 2},
+		{"search tags", &RecommendOptions{SearchTags: 1}, []int{100}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newMockAPI(t, func(req *http.Request) (int, string) {
+				var body struct {
+					Filter struct {
+						Tag []string `json:"tag"`
+					} `json:"filter"`
+				}
+				data, _ := io.ReadAll(req.Body)
+				if err := json.Unmarshal(data, &body); err != nil || len(body.Filter.Tag) != 1 {
+					return http.StatusBadRequest, `{}`
+				}
+				if page, ok := candidates[body.Filter.Tag[0]]; ok {
+					return http.StatusOK, page
+				}
+				return http.StatusOK, `{"total":0,"data":[]}`
+			})
+			recommendations, err := RecommendFromCollections(recommendCollections, tt.opts, http.DefaultClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int
+			for _, r := range recommendations {
+				ids = append(ids, r.Subject.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("recommendations = %v, want %v", ids, tt.want)
+			}
+			if n := api.count("POST /v0/search/subjects"); n != tt.wantSearches {
+				t.Errorf("searches = %d, want %d", n, tt.wantSearches)
+			}
+		})
+	}
+}
+
+func TestRecommendSubjectsInvalidType(t *testing.T) {
+	if _, err := RecommendSubjects("sai", &RecommendOptions{SubjectType: 5}, http.DefaultClient); err == nil {
+		t.Error("err = nil, want an error for an unknown subject type")
+	}
+}
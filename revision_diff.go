@@ -0,0 +1,368 @@
+/**
+ * @file 	revision_diff.go
+ * @brief 	比较同一个对象的两个编辑历史
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+FieldChange
+
+  - @brief 一个字段的变化。Field为name、name_cn、summary、infobox.<字段名>或其他字段名。
+    新增的字段Old为空，删除的字段New为空。
+*/
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+/*
+RevisionDiff
+
+  - @brief 两个编辑历史之间的差异。
+
+    【Changes】：变化的字段。
+
+    【OldText】【NewText】：用于生成统一格式差异的文本。
+*/
+type RevisionDiff struct {
+	Changes []FieldChange
+	OldText string
+	NewText string
+}
+
+/*
+DiffRevisions
+
+  - @brief 比较同一个对象的两个编辑历史的名称、简介、infobox字段和其他字段。
+
+  - @param
+
+    【old】：旧的编辑历史内容，可以由SubjectRevision等的Content()获取。
+
+    【new】：新的编辑历史内容。
+
+  - @return 返回一个*RevisionDiff。
+*/
+func DiffRevisions(old, new RevisionContent) *RevisionDiff {
+	diff := &RevisionDiff{
+		OldText: revisionText(old),
+		NewText: revisionText(new),
+	}
+	add := func(field, a, b string) {
+		if a != b {
+			diff.Changes = append(diff.Changes, FieldChange{Field: field, Old: a, New: b})
+		}
+	}
+	add("name", old.Name, new.Name)
+	add("name_cn", old.NameCN, new.NameCN)
+	add("summary", old.Summary, new.Summary)
+
+	oldFields, newFields := infoboxFieldMap(old.Infobox), infoboxFieldMap(new.Infobox)
+	for _, key := range unionKeys(oldFields, newFields) {
+		add("infobox."+key, oldFields[key], newFields[key])
+	}
+	for _, key := range unionKeys(old.Fields, new.Fields) {
+		add(key, old.Fields[key], new.Fields[key])
+	}
+	return diff
+}
+
+/*
+Unified
+
+  - @brief 生成统一格式（unified diff）的文本差异。
+
+  - @param
+
+    【oldLabel】【newLabel】：两个编辑历史的标签，如"r123"。
+
+    【context】：上下文行数，小于0时为3。
+
+  - @return 返回差异文本，没有差异时返回空字符串。
+*/
+func (d *RevisionDiff) Unified(oldLabel, newLabel string, context int) string {
+	if context < 0 {
+		context = 3
+	}
+	return unifiedDiff(splitLines(d.OldText), splitLines(d.NewText), oldLabel, newLabel, context)
+}
+
+/*
+revisionText
+
+  - @brief 将编辑历史内容转为便于逐行比较的文本
+
+  - @param
+
+    【c】：编辑历史内容
+
+  - @return 返回文本。
+*/
+func revisionText(c RevisionContent) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %s\n", c.Name)
+	fmt.Fprintf(&b, "name_cn: %s\n", c.NameCN)
+	for _, key := range unionKeys(c.Fields, nil) {
+		fmt.Fprintf(&b, "%s: %s\n", key, c.Fields[key])
+	}
+	b.WriteString("summary:\n")
+	for _, line := range splitLines(c.Summary) {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("infobox:\n")
+	for _, line := range splitLines(c.Infobox) {
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+/*
+infoboxFieldMap
+
+  - @brief 解析infobox的wiki文本，列表值每项一行，[k|v]形式的项写为"k|v"。
+    无法解析时按行处理，"|字段名=值"开始一个字段，之后不以"|"开始的行属于同一个字段
+
+  - @param
+
+    【wiki】：infobox的wiki文本
+
+  - @return 返回以字段名为键的map，重复的字段名以"#2"、"#3"区分。
+*/
+func infoboxFieldMap(wiki string) map[string]string {
+	fields := make(map[string]string)
+	add := func(name, value string) string {
+		key := name
+		for n := 2; ; n++ {
+			if _, ok := fields[key]; !ok {
+				break
+			}
+			key = fmt.Sprintf("%s#%d", name, n)
+		}
+		fields[key] = value
+		return key
+	}
+
+	if w, err := ParseWiki(wiki); err == nil {
+		for _, item := range w.Infobox {
+			if !item.IsList() {
+				add(item.Key, item.Value)
+				continue
+			}
+			values := make([]string, 0, len(item.Values))
+			for _, v := range item.Values {
+				if len(v.K) != 0 {
+					values = append(values, v.K+"|"+v.V)
+				} else {
+					values = append(values, v.V)
+				}
+			}
+			add(item.Key, strings.Join(values, "\n"))
+		}
+		return fields
+	}
+
+	key := ""
+	for _, line := range splitLines(wiki) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "{{") || trimmed == "}}":
+			key = ""
+		case strings.HasPrefix(trimmed, "|"):
+			name, value, _ := strings.Cut(trimmed[1:], "=")
+			key = add(strings.TrimSpace(name), strings.TrimSpace(value))
+		case len(key) != 0:
+			fields[key] += "\n" + trimmed
+		}
+	}
+	return fields
+}
+
+func unionKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if len(text) == 0 {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+/*
+ * @brief 逐行比较的操作
+ */
+type diffOp struct {
+	kind byte // ' '、'-'、'+'
+	line string
+}
+
+/*
+diffLines
+
+  - @brief 基于最长公共子序列的逐行比较
+
+  - @param
+
+    【a】【b】：旧文本和新文本的行
+
+  - @return 返回操作列表。
+*/
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+/*
+unifiedDiff
+
+  - @brief 生成统一格式的差异文本
+
+  - @param
+
+    【a】【b】：旧文本和新文本的行
+
+    【oldLabel】【newLabel】：标签
+
+    【context】：上下文行数
+
+  - @return 返回差异文本，没有差异时返回空字符串。
+*/
+func unifiedDiff(a, b []string, oldLabel, newLabel string, context int) string {
+	ops := diffLines(a, b)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldLabel, newLabel)
+	changed := false
+
+	for start := 0; start < len(ops); {
+		// 找到下一处变化
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		changed = true
+
+		// 向后合并间隔不超过2*context的变化
+		last := first
+		for k := first; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				last = k
+			} else if k-last > 2*context {
+				break
+			}
+		}
+		hunkStart := first - context
+		if hunkStart < start {
+			hunkStart = start
+		}
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		hunkEnd := last + context + 1
+		if hunkEnd > len(ops) {
+			hunkEnd = len(ops)
+		}
+
+		// 计算hunk在两个文本中的起始行号和行数
+		oldLine, newLine := 1, 1
+		for _, op := range ops[:hunkStart] {
+			if op.kind != '+' {
+				oldLine++
+			}
+			if op.kind != '-' {
+				newLine++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldLine--
+		}
+		if newCount == 0 {
+			newLine--
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, op := range ops[hunkStart:hunkEnd] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			out.WriteByte('\n')
+		}
+		start = hunkEnd
+	}
+
+	if !changed {
+		return ""
+	}
+	return out.String()
+}
@@ -0,0 +1,164 @@
+package lite_bangumi_api
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	old := RevisionContent{
+		Name:    "まどか",
+		NameCN:  "小圆",
+		Summary: "a",
+		Infobox: "{{Infobox animanga/TVAnime\n|中文名= 小圆\n|话数= 12\n|别名={\n[A]\n[英文|B]\n}\n}}",
+		Fields:  map[string]string{"platform": "TV"},
+	}
+	new := RevisionContent{
+		Name:    "まどか",
+		NameCN:  "魔法少女小圆",
+		Summary: "a",
+		Infobox: "{{Infobox animanga/TVAnime\n|中文名= 魔法少女小圆\n|别名={\n[A]\n[英文|C]\n}\n|放送开始= 2011年1月7日\n}}",
+		Fields:  map[string]string{"platform": "TV"},
+	}
+	want := []FieldChange{
+		{Field: "name_cn", Old: "小圆", New: "魔法少女小圆"},
+		{Field: "infobox.中文名", Old: "小圆", New: "魔法少女小圆"},
+		{Field: "infobox.别名", Old: "A\n英文|B", New: "A\n英文|C"},
+		{Field: "infobox.放送开始", Old: "", New: "2011年1月7日"},
+		{Field: "infobox.话数", Old: "12", New: ""},
+	}
+	got := DiffRevisions(old, new).Changes
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes = %#v, want %#v", got, want)
+	}
+
+	if changes := DiffRevisions(old, old).Changes; len(changes) != 0 {
+		t.Errorf("identical revisions: Changes = %#v, want none", changes)
+	}
+}
+
+func TestInfoboxFieldMapDuplicatesAndFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		wiki string
+		want map[string]string
+	}{
+		{
+			name: "duplicate keys",
+			wiki: "{{Infobox\n|导演= A\n|导演= B\n}}",
+			want: map[string]string{"导演": "A", "导演#2": "B"},
+		},
+		{
+			name: "unparsable falls back to lines",
+			wiki: "{{Infobox\n|简介= 第一行\n第二行\n|话数= 12",
+			want: map[string]string{"简介": "第一行\n第二行", "话数": "12"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := infoboxFieldMap(tt.wiki); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("infoboxFieldMap = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = "line" + strconv.Itoa(i+1)
+	}
+	return lines
+}
+
+func replaceLine(lines []string, i int, s string) []string {
+	out := append([]string{}, lines...)
+	out[i] = s
+	return out
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	base := numberedLines(20)
+	tests := []struct {
+		name    string
+		a, b    []string
+		context int
+		want    string
+	}{
+		{
+			name:    "no change",
+			a:       base,
+			b:       base,
+			context: 3,
+			want:    "",
+		},
+		{
+			name:    "single change in the middle",
+			a:       base,
+			b:       replaceLine(base, 9, "x"),
+			context: 2,
+			want: "--- a\n+++ b\n@@ -8,5 +8,5 @@\n" +
+				" line8\n line9\n-line10\n+x\n line11\n line12\n",
+		},
+		{
+			name:    "changes 2*context apart share a hunk",
+			a:       base,
+			b:       replaceLine(replaceLine(base, 4, "x"), 9, "y"),
+			context: 2,
+			want: "--- a\n+++ b\n@@ -3,10 +3,10 @@\n" +
+				" line3\n line4\n-line5\n+x\n line6\n line7\n line8\n line9\n-line10\n+y\n line11\n line12\n",
+		},
+		{
+			name:    "changes 2*context+1 apart get two hunks",
+			a:       base,
+			b:       replaceLine(replaceLine(base, 4, "x"), 10, "y"),
+			context: 2,
+			want: "--- a\n+++ b\n@@ -3,5 +3,5 @@\n" +
+				" line3\n line4\n-line5\n+x\n line6\n line7\n" +
+				"@@ -9,5 +9,5 @@\n" +
+				" line9\n line10\n-line11\n+y\n line12\n line13\n",
+		},
+		{
+			name:    "insertion at the start",
+			a:       base[:3],
+			b:       append([]string{"x"}, base[:3]...),
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,1 +1,2 @@\n+x\n line1\n",
+		},
+		{
+			name:    "insertion into empty text",
+			a:       nil,
+			b:       []string{"x"},
+			context: 3,
+			want:    "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+x\n",
+		},
+		{
+			name:    "deletion at the end",
+			a:       base[:3],
+			b:       base[:2],
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -2,2 +2,1 @@\n line2\n-line3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff(tt.a, tt.b, "a", "b", tt.context); got != tt.want {
+				t.Errorf("unifiedDiff =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevisionDiffUnified(t *testing.T) {
+	old := RevisionContent{Name: "a", Summary: "x\r\ny"}
+	new := RevisionContent{Name: "a", Summary: "x\r\nz"}
+	got := DiffRevisions(old, new).Unified("r1", "r2", -1)
+	if !strings.HasPrefix(got, "--- r1\n+++ r2\n@@ ") || !strings.Contains(got, "\n-y\n+z\n") {
+		t.Errorf("Unified =\n%s", got)
+	}
+	if got := DiffRevisions(old, old).Unified("r1", "r2", 3); got != "" {
+		t.Errorf("Unified without changes = %q, want empty", got)
+	}
+}
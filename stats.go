@@ -0,0 +1,273 @@
+/**
+ * @file 	stats.go
+ * @brief 	用户收藏的统计报告
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+/*
+CollectionStatsOptions
+
+  - @brief 统计收藏的选项。
+
+    【EpisodeMinutes】：每集时长（分钟），键为条目类型，为nil时动画为24分钟、三次元为45分钟。
+    不在其中的条目类型不计入观看时长。
+
+    【TopTags】：最多保留的标签数，小于等于0时为20。
+*/
+type CollectionStatsOptions struct {
+	EpisodeMinutes map[int]int
+	TopTags        int
+}
+
+/*
+CollectionStats
+
+  - @brief 用户收藏的统计结果。条目类型和收藏类型以名称表示，如"动漫"、"看过"。
+
+    【ByType】【ByState】【ByTypeAndState】：各条目类型、各收藏类型的收藏数。
+
+    【RatingDistribution】：评分分布，下标0为1分，下标9为10分。
+
+    【MeanRating】：平均评分，不包括未评分的收藏。
+
+    【TopTags】：用户最常用的标签。
+
+    【WatchedEpisodes】【WatchMinutes】：看过的集数和估计的观看时长。看过的条目按条目集数计算，其他条目按进度计算。
+
+    【CompletionRate】【DropRate】：各条目类型的看过、抛弃比例，分母为想看以外的收藏数。
+
+    【ActivityByYear】：按收藏更新时间统计的每年收藏数。
+*/
+type CollectionStats struct {
+	UserName           string                    `json:"username"`
+	Total              int                       `json:"total"`
+	ByType             map[string]int            `json:"by_type"`
+	ByState            map[string]int            `json:"by_state"`
+	ByTypeAndState     map[string]map[string]int `json:"by_type_and_state"`
+	RatingCount        int                       `json:"rating_count"`
+	RatingDistribution [10]int                   `json:"rating_distribution"`
+	MeanRating         float64                   `json:"mean_rating"`
+	TopTags            []Tag                     `json:"top_tags"`
+	WatchedEpisodes    int                       `json:"watched_episodes"`
+	WatchMinutes       int                       `json:"watch_minutes"`
+	CompletionRate     map[string]float64        `json:"completion_rate"`
+	DropRate           map[string]float64        `json:"drop_rate"`
+	ActivityByYear     map[int]int               `json:"activity_by_year"`
+}
+
+/*
+Stats
+
+  - @brief 统计导出的收藏。
+
+  - @param
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回一个*CollectionStats。
+*/
+func (e *CollectionExport) Stats(opts *CollectionStatsOptions) *CollectionStats {
+	episodeMinutes := map[int]int{2: 24, 6: 45}
+	topTags := 20
+	if opts != nil {
+		if opts.EpisodeMinutes != nil {
+			episodeMinutes = opts.EpisodeMinutes
+		}
+		if opts.TopTags > 0 {
+			topTags = opts.TopTags
+		}
+	}
+
+	s := &CollectionStats{
+		UserName:       e.UserName,
+		Total:          len(e.Collections),
+		ByType:         make(map[string]int),
+		ByState:        make(map[string]int),
+		ByTypeAndState: make(map[string]map[string]int),
+		CompletionRate: make(map[string]float64),
+		DropRate:       make(map[string]float64),
+		ActivityByYear: make(map[int]int),
+	}
+	tags := make(map[string]int)
+	ratingSum := 0
+
+	for _, c := range e.Collections {
+		typeName := SubjectTypeName(c.SubjectType)
+		stateName := CollectionTypeName(c.Type)
+		s.ByType[typeName]++
+		s.ByState[stateName]++
+		if s.ByTypeAndState[typeName] == nil {
+			s.ByTypeAndState[typeName] = make(map[string]int)
+		}
+		s.ByTypeAndState[typeName][stateName]++
+
+		if c.Rate >= 1 && c.Rate <= 10 {
+			s.RatingCount++
+			s.RatingDistribution[c.Rate-1]++
+			ratingSum += c.Rate
+		}
+		for _, tag := range c.Tags {
+			if tag = strings.TrimSpace(tag); len(tag) != 0 {
+				tags[tag]++
+			}
+		}
+		if !c.UpdatedAt.IsZero() {
+			s.ActivityByYear[c.UpdatedAt.Year()]++
+		}
+
+		if minutes, ok := episodeMinutes[c.SubjectType]; ok {
+			episodes := c.EpStatus
+			if stateName == "看过" && c.Subject.Eps > episodes {
+				episodes = c.Subject.Eps
+			}
+			s.WatchedEpisodes += episodes
+			s.WatchMinutes += episodes * minutes
+		}
+	}
+
+	if s.RatingCount != 0 {
+		s.MeanRating = float64(ratingSum) / float64(s.RatingCount)
+	}
+	for typeName, states := range s.ByTypeAndState {
+		started := s.ByType[typeName] - states["想看"]
+		if started != 0 {
+			s.CompletionRate[typeName] = float64(states["看过"]) / float64(started)
+			s.DropRate[typeName] = float64(states["抛弃"]) / float64(started)
+		}
+	}
+
+	for name, count := range tags {
+		s.TopTags = append(s.TopTags, Tag{Name: name, Count: count})
+	}
+	sort.Slice(s.TopTags, func(i, j int) bool {
+		if s.TopTags[i].Count != s.TopTags[j].Count {
+			return s.TopTags[i].Count > s.TopTags[j].Count
+		}
+		return s.TopTags[i].Name < s.TopTags[j].Name
+	})
+	if len(s.TopTags) > topTags {
+		s.TopTags = s.TopTags[:topTags]
+	}
+	return s
+}
+
+/*
+GetCollectionStats
+
+  - @brief 获取用户全部条目收藏并统计，相当于ExportCollections后调用Stats。
+
+    API：/v0/users/{username}/collections
+
+  - @param
+
+    【userName】：用户名。
+
+    【opts】：选项，可以为nil。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*CollectionStats和一个err。
+
+  - @retval *CollectionStats是统计结果，err表示错误。如果err为nil，则没有错误。
+*/
+func GetCollectionStats(userName string, opts *CollectionStatsOptions, client *http.Client) (*CollectionStats, error) {
+	export, err := ExportCollections(userName, client)
+	if err != nil {
+		return nil, err
+	}
+	return export.Stats(opts), nil
+}
+
+/*
+WriteJSON
+
+  - @brief 以JSON格式写出统计结果。
+
+  - @param
+
+    【w】：io.Writer对象。
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func (s *CollectionStats) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+/*
+Markdown
+
+  - @brief 将统计结果渲染为Markdown。
+
+  - @return 返回Markdown文本。
+*/
+func (s *CollectionStats) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s 的收藏统计\n\n", s.UserName)
+	fmt.Fprintf(&b, "共 %d 个收藏。\n\n", s.Total)
+
+	b.WriteString("## 收藏数\n\n")
+	b.WriteString("| 类型 |")
+	for _, state := range CollectionTypeNames {
+		b.WriteString(" " + state + " |")
+	}
+	b.WriteString(" 合计 | 完成率 | 抛弃率 |\n")
+	b.WriteString("| --- |" + strings.Repeat(" ---: |", len(CollectionTypeNames)+3) + "\n")
+	for _, typeName := range SubjectTypeNames {
+		states, ok := s.ByTypeAndState[typeName]
+		if !ok {
+			continue
+		}
+		b.WriteString("| " + typeName + " |")
+		for _, state := range CollectionTypeNames {
+			fmt.Fprintf(&b, " %d |", states[state])
+		}
+		fmt.Fprintf(&b, " %d | %.1f%% | %.1f%% |\n", s.ByType[typeName], s.CompletionRate[typeName]*100, s.DropRate[typeName]*100)
+	}
+
+	b.WriteString("\n## 评分\n\n")
+	fmt.Fprintf(&b, "评分 %d 个，平均 %.2f 分。\n\n", s.RatingCount, s.MeanRating)
+	b.WriteString("| 分数 | 数量 |\n| ---: | ---: |\n")
+	for i := len(s.RatingDistribution) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "| %d | %d |\n", i+1, s.RatingDistribution[i])
+	}
+
+	if len(s.TopTags) != 0 {
+		b.WriteString("\n## 常用标签\n\n")
+		b.WriteString("| 标签 | 次数 |\n| --- | ---: |\n")
+		for _, tag := range s.TopTags {
+			fmt.Fprintf(&b, "| %s | %d |\n", strings.ReplaceAll(tag.Name, "|", `\|`), tag.Count)
+		}
+	}
+
+	b.WriteString("\n## 观看时长\n\n")
+	fmt.Fprintf(&b, "共 %d 集，约 %d 小时 %d 分钟。\n", s.WatchedEpisodes, s.WatchMinutes/60, s.WatchMinutes%60)
+
+	if len(s.ActivityByYear) != 0 {
+		years := make([]int, 0, len(s.ActivityByYear))
+		for year := range s.ActivityByYear {
+			years = append(years, year)
+		}
+		sort.Ints(years)
+		b.WriteString("\n## 每年收藏\n\n")
+		b.WriteString("| 年份 | 收藏数 |\n| ---: | ---: |\n")
+		for _, year := range years {
+			fmt.Fprintf(&b, "| %d | %d |\n", year, s.ActivityByYear[year])
+		}
+	}
+	return b.String()
+}
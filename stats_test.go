@@ -0,0 +1,117 @@
+package lite_bangumi_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var statsExport = &CollectionExport{
+	UserName: "sai",
+	Collections: []UserSubjectCollection{
+		{SubjectID: 1, SubjectType: 2, Type: 2, Rate: 8, Tags: []string{"a", "b"}, EpStatus: 5, Subject: SlimSubject{Eps: 12},
+			UpdatedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{SubjectID: 2, SubjectType: 2, Type: 5, Rate: 3, Tags: []string{"a"}, EpStatus: 2, Subject: SlimSubject{Eps: 12},
+			UpdatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{SubjectID: 3, SubjectType: 2, Type: 1, Tags: []string{" ", "c|d"},
+			UpdatedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{SubjectID: 4, SubjectType: 6, Type: 3, Rate: 10, EpStatus: 4,
+			UpdatedAt: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{SubjectID: 5, SubjectType: 1, Type: 2, EpStatus: 3},
+	},
+}
+
+func TestCollectionStats(t *testing.T) {
+	s := statsExport.Stats(nil)
+
+	if want := map[string]int{"动漫": 3, "三次元": 1, "书籍": 1}; !reflect.DeepEqual(s.ByType, want) {
+		t.Errorf("ByType = %v, want %v", s.ByType, want)
+	}
+	if want := map[string]int{"想看": 1, "看过": 2, "在看": 1, "抛弃": 1}; !reflect.DeepEqual(s.ByState, want) {
+		t.Errorf("ByState = %v, want %v", s.ByState, want)
+	}
+	if s.RatingCount != 3 || s.MeanRating != 7 || s.RatingDistribution != [10]int{2: 1, 7: 1, 9: 1} {
+		t.Errorf("ratings = %d %v %v", s.RatingCount, s.MeanRating, s.RatingDistribution)
+	}
+	if want := []Tag{{"a", 2}, {"b", 1}, {"c|d", 1}}; !reflect.DeepEqual(s.TopTags, want) {
+		t.Errorf("TopTags = %v, want %v", s.TopTags, want)
+	}
+	if want := map[int]int{2025: 1, 2026: 3}; !reflect.DeepEqual(s.ActivityByYear, want) {
+		t.Errorf("ActivityByYear = %v, want %v", s.ActivityByYear, want)
+	}
+
+	rates := []struct {
+		typeName       string
+		wantCompletion float64
+		wantDrop       float64
+	}{
+		{"动漫", 0.5, 0.5},
+		{"三次元", 0, 0},
+		{"书籍", 1, 0},
+	}
+	for _, tt := range rates {
+		if math.Abs(s.CompletionRate[tt.typeName]-tt.wantCompletion) > 1e-9 || math.Abs(s.DropRate[tt.typeName]-tt.wantDrop) > 1e-9 {
+			t.Errorf("%s rates = %v %v, want %v %v", tt.typeName, s.CompletionRate[tt.typeName], s.DropRate[tt.typeName], tt.wantCompletion, tt.wantDrop)
+		}
+	}
+}
+
+func TestCollectionStatsWatchTime(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         *CollectionStatsOptions
+		wantEpisodes int
+		wantMinutes  int
+		wantTags     int
+	}{
+		// 看过的条目按条目集数计算，书籍不计入
+		{"default minutes", nil, 12 + 2 + 4, 12*24 + 2*24 + 4*45, 3},
+		{"custom minutes", &CollectionStatsOptions{EpisodeMinutes: map[int]int{2: 10}, TopTags: 2}, 12 + 2, (12 + 2) * 10, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := statsExport.Stats(tt.opts)
+			if s.WatchedEpisodes != tt.wantEpisodes || s.WatchMinutes != tt.wantMinutes || len(s.TopTags) != tt.wantTags {
+				t.Errorf("episodes %d minutes %d tags %d, want %d %d %d", s.WatchedEpisodes, s.WatchMinutes, len(s.TopTags), tt.wantEpisodes, tt.wantMinutes, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestCollectionStatsOutput(t *testing.T) {
+	s := statsExport.Stats(nil)
+	md := s.Markdown()
+	for _, want := range []string{
+		"# sai 的收藏统计\n",
+		"| 动漫 | 1 | 1 | 0 | 0 | 1 | 3 | 50.0% | 50.0% |\n",
+		"评分 3 个，平均 7.00 分。",
+		"| 8 | 1 |\n",
+		`| c\|d | 1 |`,
+		"共 18 集，约 8 小时 36 分钟。",
+		"| 2025 | 1 |\n| 2026 | 3 |\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown does not contain %q:\n%s", want, md)
+		}
+	}
+	// 没有收藏的条目类型不输出
+	if strings.Contains(md, "| 音乐 |") {
+		t.Error("Markdown contains an empty subject type")
+	}
+
+	var b bytes.Buffer
+	if err := s.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	var decoded CollectionStats
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, s) {
+		t.Errorf("JSON round trip = %+v, want %+v", decoded, *s)
+	}
+}
@@ -0,0 +1,301 @@
+/**
+ * @file 	taste_match.go
+ * @brief 	比较两个用户的收藏，计算口味相似度
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"math"
+	"net/http"
+	"sort"
+)
+
+/*
+TasteMatchOptions
+
+  - @brief 比较收藏的选项。
+
+    【MinRating】：推荐的最低评分，小于等于0时为8。
+
+    【Top】：分歧和推荐最多保留的数量，小于等于0时为10。
+*/
+type TasteMatchOptions struct {
+	MinRating int
+	Top       int
+}
+
+/*
+ * @brief 两个用户对同一个条目的评分，未评分为0
+ */
+type RatedPair struct {
+	Subject SubjectRef `json:"subject"`
+	RateA   int        `json:"rate_a"`
+	RateB   int        `json:"rate_b"`
+}
+
+/*
+TasteMatch
+
+  - @brief 两个用户收藏的比较结果。
+
+    【Shared】：两个用户都收藏的条目（不包括想看），按条目ID排序。
+
+    【RatedBoth】：两个用户都评分的条目数。
+
+    【Pearson】【Spearman】：共同评分的皮尔逊相关系数和斯皮尔曼等级相关系数，共同评分少于2个或评分没有变化时为0。
+
+    【Score】：相似度，0到100。以皮尔逊相关系数换算，共同评分越少越接近50。
+
+    【Disagreements】：评分差距最大的条目，不包括评分相同的条目。
+
+    【RecommendForA】：B评分较高而A没有看过（没有收藏或只是想看）的条目，按B的评分排序。
+
+    【RecommendForB】：A评分较高而B没有看过的条目，按A的评分排序。
+*/
+type TasteMatch struct {
+	UserA         string       `json:"user_a"`
+	UserB         string       `json:"user_b"`
+	Shared        []SubjectRef `json:"shared"`
+	RatedBoth     int          `json:"rated_both"`
+	Pearson       float64      `json:"pearson"`
+	Spearman      float64      `json:"spearman"`
+	Score         float64      `json:"score"`
+	Disagreements []RatedPair  `json:"disagreements"`
+	RecommendForA []RatedPair  `json:"recommend_for_a"`
+	RecommendForB []RatedPair  `json:"recommend_for_b"`
+}
+
+/*
+CompareUsers
+
+  - @brief 获取两个用户的全部收藏并比较。
+
+    API：/v0/users/{username}/collections
+
+  - @param
+
+    【userA】【userB】：用户名。
+
+    【opts】：选项，可以为nil。
+
+    【client】：http.Client对象。
+
+  - @return 返回一个*TasteMatch和一个err。
+
+  - @retval *TasteMatch是比较结果，err表示错误。如果err为nil，则没有错误。
+*/
+func CompareUsers(userA, userB string, opts *TasteMatchOptions, client *http.Client) (*TasteMatch, error) {
+	a, err := ExportCollections(userA, client)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ExportCollections(userB, client)
+	if err != nil {
+		return nil, err
+	}
+	return CompareExports(a, b, opts), nil
+}
+
+/*
+CompareExports
+
+  - @brief 比较两个用户导出的收藏。
+
+  - @param
+
+    【a】【b】：两个用户的收藏，可以由ExportCollections或LoadCollectionExportJSON获取。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回一个*TasteMatch。
+*/
+func CompareExports(a, b *CollectionExport, opts *TasteMatchOptions) *TasteMatch {
+	minRating, top := 8, 10
+	if opts != nil {
+		if opts.MinRating > 0 {
+			minRating = opts.MinRating
+		}
+		if opts.Top > 0 {
+			top = opts.Top
+		}
+	}
+
+	m := &TasteMatch{UserA: a.UserName, UserB: b.UserName}
+	collectionsA, collectionsB := seenCollections(a), seenCollections(b)
+
+	var xs, ys []float64
+	for id, ca := range collectionsA {
+		cb, ok := collectionsB[id]
+		if !ok {
+			if ca.Rate >= minRating {
+				m.RecommendForB = append(m.RecommendForB, ratedPair(ca, ca.Rate, 0))
+			}
+			continue
+		}
+		m.Shared = append(m.Shared, collectionSubjectRef(ca))
+		if ca.Rate > 0 && cb.Rate > 0 {
+			xs = append(xs, float64(ca.Rate))
+			ys = append(ys, float64(cb.Rate))
+			if ca.Rate != cb.Rate {
+				m.Disagreements = append(m.Disagreements, ratedPair(ca, ca.Rate, cb.Rate))
+			}
+		}
+	}
+	for id, cb := range collectionsB {
+		if _, ok := collectionsA[id]; !ok && cb.Rate >= minRating {
+			m.RecommendForA = append(m.RecommendForA, ratedPair(cb, 0, cb.Rate))
+		}
+	}
+
+	m.RatedBoth = len(xs)
+	m.Pearson = pearson(xs, ys)
+	m.Spearman = pearson(ranks(xs), ranks(ys))
+	// 共同评分较少时向50收缩
+	confidence := float64(m.RatedBoth) / float64(m.RatedBoth+10)
+	m.Score = 50 + 50*m.Pearson*confidence
+
+	sort.Slice(m.Shared, func(i, j int) bool { return m.Shared[i].ID < m.Shared[j].ID })
+	sortRatedPairs(m.Disagreements, func(p RatedPair) int {
+		d := p.RateA - p.RateB
+		if d < 0 {
+			d = -d
+		}
+		return d
+	})
+	sortRatedPairs(m.RecommendForA, func(p RatedPair) int { return p.RateB })
+	sortRatedPairs(m.RecommendForB, func(p RatedPair) int { return p.RateA })
+	if len(m.Disagreements) > top {
+		m.Disagreements = m.Disagreements[:top]
+	}
+	if len(m.RecommendForA) > top {
+		m.RecommendForA = m.RecommendForA[:top]
+	}
+	if len(m.RecommendForB) > top {
+		m.RecommendForB = m.RecommendForB[:top]
+	}
+	return m
+}
+
+/*
+seenCollections
+
+  - @brief 获取想看以外的收藏
+
+  - @param
+
+    【e】：导出的收藏
+
+  - @return 返回以条目ID为键的收藏。
+*/
+func seenCollections(e *CollectionExport) map[int]UserSubjectCollection {
+	collections := make(map[int]UserSubjectCollection, len(e.Collections))
+	for _, c := range e.Collections {
+		if CollectionTypeName(c.Type) != "想看" {
+			collections[c.SubjectID] = c
+		}
+	}
+	return collections
+}
+
+func collectionSubjectRef(c UserSubjectCollection) SubjectRef {
+	return SubjectRef{ID: c.SubjectID, Name: c.Subject.Name, NameCN: c.Subject.NameCN}
+}
+
+func ratedPair(c UserSubjectCollection, rateA, rateB int) RatedPair {
+	return RatedPair{Subject: collectionSubjectRef(c), RateA: rateA, RateB: rateB}
+}
+
+/*
+sortRatedPairs
+
+  - @brief 按key从大到小排序，key相同时按条目ID排序
+
+  - @param
+
+    【pairs】：评分
+
+    【key】：排序键
+*/
+func sortRatedPairs(pairs []RatedPair, key func(RatedPair) int) {
+	sort.Slice(pairs, func(i, j int) bool {
+		ki, kj := key(pairs[i]), key(pairs[j])
+		if ki != kj {
+			return ki > kj
+		}
+		return pairs[i].Subject.ID < pairs[j].Subject.ID
+	})
+}
+
+/*
+pearson
+
+  - @brief 皮尔逊相关系数
+
+  - @param
+
+    【xs】【ys】：长度相同的两组数据
+
+  - @return 返回相关系数，数据少于2个或没有变化时返回0。
+*/
+func pearson(xs, ys []float64) float64 {
+	n := len(xs)
+	if n < 2 || n != len(ys) {
+		return 0
+	}
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+/*
+ranks
+
+  - @brief 计算等级，相同的值取平均等级
+
+  - @param
+
+    【xs】：数据
+
+  - @return 返回与xs顺序相同的等级。
+*/
+func ranks(xs []float64) []float64 {
+	order := make([]int, len(xs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return xs[order[i]] < xs[order[j]] })
+
+	result := make([]float64, len(xs))
+	for i := 0; i < len(order); {
+		j := i
+		for j < len(order) && xs[order[j]] == xs[order[i]] {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			result[order[k]] = rank
+		}
+		i = j
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package lite_bangumi_api
+
+import (
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		want   float64
+	}{
+		{"perfect", []float64{1, 2, 3}, []float64{2, 4, 6}, 1},
+		{"inverse", []float64{1, 2, 3}, []float64{9, 6, 3}, -1},
+		{"partial", []float64{1, 2, 3, 4}, []float64{1, 3, 2, 4}, 0.8},
+		{"constant", []float64{5, 5, 5}, []float64{1, 2, 3}, 0},
+		{"single pair", []float64{1}, []float64{2}, 0},
+		{"length mismatch", []float64{1, 2}, []float64{1, 2, 3}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pearson(tt.xs, tt.ys); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("pearson = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRanks(t *testing.T) {
+	tests := []struct {
+		xs   []float64
+		want []float64
+	}{
+		{[]float64{10, 20, 30}, []float64{1, 2, 3}},
+		{[]float64{10, 20, 20, 5}, []float64{2, 3.5, 3.5, 1}},
+		{[]float64{7, 7, 7}, []float64{2, 2, 2}},
+		{[]float64{}, []float64{}},
+	}
+	for _, tt := range tests {
+		if got := ranks(tt.xs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ranks(%v) = %v, want %v", tt.xs, got, tt.want)
+		}
+	}
+
+	// 单调但非线性时斯皮尔曼为1
+	xs, ys := []float64{1, 2, 3, 4}, []float64{1, 4, 9, 100}
+	if got := pearson(ranks(xs), ranks(ys)); math.Abs(got-1) > 1e-9 {
+		t.Errorf("spearman = %v, want 1", got)
+	}
+	if got := pearson(xs, ys); got >= 1-1e-9 {
+		t.Errorf("pearson = %v, want less than 1", got)
+	}
+}
+
+func tasteExport(userName string, rates map[int][2]int) *CollectionExport {
+	e := &CollectionExport{UserName: userName}
+	for id, r := range rates {
+		e.Collections = append(e.Collections, UserSubjectCollection{SubjectID: id, Type: r[0], Rate: r[1]})
+	}
+	return e
+}
+
+func pairIDs(pairs []RatedPair) []int {
+	var ids []int
+	for _, p := range pairs {
+		ids = append(ids, p.Subject.ID)
+	}
+	return ids
+}
+
+func TestCompareExports(t *testing.T) {
+	// 收藏类型、评分
+	a := tasteExport("a", map[int][2]int{1: {2, 8}, 2: {2, 6}, 3: {2, 9}, 4: {1, 0}, 5: {2, 9}, 7: {2, 7}})
+	b := tasteExport("b", map[int][2]int{1: {2, 8}, 2: {2, 9}, 3: {2, 0}, 4: {2, 10}, 6: {3, 8}})
+
+	tests := []struct {
+		name              string
+		opts              *TasteMatchOptions
+		wantRecommendForA []int
+		wantRecommendForB []int
+	}{
+		{"default", nil, []int{4, 6}, []int{5}},
+		{"min rating", &TasteMatchOptions{MinRating: 7}, []int{4, 6}, []int{5, 7}},
+		{"top", &TasteMatchOptions{Top: 1}, []int{4}, []int{5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := CompareExports(a, b, tt.opts)
+			var shared []int
+			for _, s := range m.Shared {
+				shared = append(shared, s.ID)
+			}
+			if want := []int{1, 2, 3}; !reflect.DeepEqual(shared, want) {
+				t.Errorf("Shared = %v, want %v", shared, want)
+			}
+			if m.RatedBoth != 2 || math.Abs(m.Pearson+1) > 1e-9 || math.Abs(m.Spearman+1) > 1e-9 {
+				t.Errorf("RatedBoth %d Pearson %v Spearman %v, want 2 -1 -1", m.RatedBoth, m.Pearson, m.Spearman)
+			}
+			if want := 50 - 50*2.0/12; math.Abs(m.Score-want) > 1e-9 {
+				t.Errorf("Score = %v, want %v", m.Score, want)
+			}
+			if got := pairIDs(m.Disagreements); !reflect.DeepEqual(got, []int{2}) || m.Disagreements[0].RateA != 6 || m.Disagreements[0].RateB != 9 {
+				t.Errorf("Disagreements = %+v, want subject 2 rated 6 and 9", m.Disagreements)
+			}
+			if got := pairIDs(m.RecommendForA); !reflect.DeepEqual(got, tt.wantRecommendForA) {
+				t.Errorf("RecommendForA = %v, want %v", got, tt.wantRecommendForA)
+			}
+			if got := pairIDs(m.RecommendForB); !reflect.DeepEqual(got, tt.wantRecommendForB) {
+				t.Errorf("RecommendForB = %v, want %v", got, tt.wantRecommendForB)
+			}
+		})
+	}
+}
+
+func TestCompareUsers(t *testing.T) {
+	newMockAPI(t, func(req *http.Request) (int, string) {
+		switch req.URL.Path {
+		case "/v0/users/a/collections":
+			return http.StatusOK, `{"total":1,"data":[{"subject_id":1,"subject_type":2,"type":2,"rate":8,"subject":{"name":"S1"}}]}`
+		case "/v0/users/b/collections":
+			return http.StatusOK, `{"total":1,"data":[{"subject_id":1,"subject_type":2,"type":2,"rate":7}]}`
+		}
+		return http.StatusNotFound, `{}`
+	})
+	m, err := CompareUsers("a", "b", nil, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.UserA != "a" || m.UserB != "b" || len(m.Shared) != 1 || m.Shared[0].Name != "S1" || m.RatedBoth != 1 || m.Score != 50 {
+		t.Errorf("CompareUsers = %+v", m)
+	}
+
+	if _, err = CompareUsers("a", "nobody", nil, http.DefaultClient); err == nil {
+		t.Error("unknown user: err = nil, want an error")
+	}
+}
@@ -0,0 +1,231 @@
+/**
+ * @file 	token_source.go
+ * @brief 	token来源接口及其实现
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+TokenSource
+
+  - @brief token来源接口。每次请求时都会调用Token()获取token。
+
+    Token()返回空字符串表示匿名访问，请求中不会携带Authorization头。
+*/
+type TokenSource interface {
+	Token() (string, error)
+}
+
+/*
+ * @brief 固定token
+ */
+type staticTokenSource string
+
+func (s staticTokenSource) Token() (string, error) {
+	return string(s), nil
+}
+
+/*
+StaticTokenSource
+
+  - @brief 返回一个始终返回同一个token的TokenSource。
+
+  - @param
+
+    【token】：token（不需要写入Bearer）。
+
+  - @return 返回一个TokenSource。
+*/
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+/*
+ * @brief 从环境变量读取token
+ */
+type envTokenSource string
+
+func (s envTokenSource) Token() (string, error) {
+	return strings.TrimSpace(os.Getenv(string(s))), nil
+}
+
+/*
+EnvTokenSource
+
+  - @brief 返回一个每次请求时从环境变量读取token的TokenSource。环境变量不存在时为匿名访问。
+
+  - @param
+
+    【name】：环境变量名，如BANGUMI_TOKEN。
+
+  - @return 返回一个TokenSource。
+*/
+func EnvTokenSource(name string) TokenSource {
+	return envTokenSource(name)
+}
+
+/*
+ * @brief 从文件读取token
+ */
+type fileTokenSource string
+
+func (s fileTokenSource) Token() (string, error) {
+	data, err := os.ReadFile(string(s))
+	if err != nil {
+		errMsg := errors.New("FileTokenSource：读取token文件失败")
+		return "", errMsg
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+/*
+FileTokenSource
+
+  - @brief 返回一个每次请求时从文件读取token的TokenSource。文件内容首尾的空白会被去掉。
+
+  - @param
+
+    【path】：token文件路径。
+
+  - @return 返回一个TokenSource。
+*/
+func FileTokenSource(path string) TokenSource {
+	return fileTokenSource(path)
+}
+
+/*
+OAuthTokenSource
+
+  - @brief 使用refresh token自动刷新access token的TokenSource。
+
+    API：https://bgm.tv/oauth/access_token
+
+    access token过期前一分钟会自动刷新，刷新后RefreshToken会更新为新的refresh token。
+*/
+type OAuthTokenSource struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+	RefreshToken string
+	Client       *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+/*
+NewOAuthTokenSource
+
+  - @brief 创建一个OAuthTokenSource。
+
+  - @param
+
+    【clientID】：App ID。
+
+    【clientSecret】：App Secret。
+
+    【refreshToken】：refresh token。
+
+    【redirectURI】：回调地址。
+
+    【client】：http.Client对象，用于刷新token。
+
+  - @return 返回一个*OAuthTokenSource。
+*/
+func NewOAuthTokenSource(clientID, clientSecret, refreshToken, redirectURI string, client *http.Client) *OAuthTokenSource {
+	return &OAuthTokenSource{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURI:  redirectURI,
+		RefreshToken: refreshToken,
+		Client:       client,
+	}
+}
+
+/*
+Token
+
+  - @brief 获取access token，过期时自动刷新。
+
+  - @return 返回一个string和一个err。
+
+  - @retval string是access token，err表示错误。如果err为nil，则没有错误。
+*/
+func (s *OAuthTokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.accessToken) != 0 && time.Now().Add(time.Minute).Before(s.expiry) {
+		return s.accessToken, nil
+	}
+	if err := s.refresh(); err != nil {
+		return "", err
+	}
+	return s.accessToken, nil
+}
+
+func (s *OAuthTokenSource) refresh() error {
+	form := url.Values{}
+	form.Add("grant_type", "refresh_token")
+	form.Add("client_id", s.ClientID)
+	form.Add("client_secret", s.ClientSecret)
+	form.Add("refresh_token", s.RefreshToken)
+	form.Add("redirect_uri", s.RedirectURI)
+
+	req, err := http.NewRequest("POST", "https://bgm.tv/oauth/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		errMsg := errors.New("OAuthTokenSource：不正确的请求")
+		return errMsg
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", UserAgent)
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		errMsg := errors.New("OAuthTokenSource：连接失败或超时")
+		return errMsg
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := errors.New("OAuthTokenSource：错误的返回码:" + strconv.Itoa(resp.StatusCode))
+		return errMsg
+	}
+
+	var result struct {
+		AccessToken  string `json:"access_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result.AccessToken) == 0 {
+		errMsg := errors.New("OAuthTokenSource：获取token失败")
+		return errMsg
+	}
+
+	s.accessToken = result.AccessToken
+	s.expiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	if len(result.RefreshToken) != 0 {
+		s.RefreshToken = result.RefreshToken
+	}
+	return nil
+}
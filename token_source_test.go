@@ -0,0 +1,74 @@
+package lite_bangumi_api
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOAuthTokenSourceRefresh(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantToken    string
+		wantRefresh  string
+		wantRequests int
+		wantErr      bool
+	}{
+		{"cached until expiry", http.StatusOK, `{"access_token":"a","expires_in":3600,"refresh_token":"r2"}`, "a", "r2", 1, false},
+		{"refresh token kept when not rotated", http.StatusOK, `{"access_token":"a","expires_in":3600}`, "a", "r1", 1, false},
+		{"refreshed again within a minute of expiry", http.StatusOK, `{"access_token":"a","expires_in":30}`, "a", "r1", 2, false},
+		{"error status", http.StatusUnauthorized, `{}`, "", "r1", 2, true},
+		{"missing access token", http.StatusOK, `{"expires_in":3600}`, "", "r1", 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				requests++
+				body, _ := io.ReadAll(req.Body)
+				if req.URL.String() != "https://bgm.tv/oauth/access_token" || !strings.Contains(string(body), "grant_type=refresh_token") {
+					t.Errorf("request = %s %s", req.URL, body)
+				}
+				return &http.Response{StatusCode: tt.status, Body: io.NopCloser(strings.NewReader(tt.body))}, nil
+			})}
+			s := NewOAuthTokenSource("id", "secret", "r1", "https://example.com/cb", client)
+
+			var token string
+			var err error
+			for i := 0; i < 2; i++ {
+				token, err = s.Token()
+			}
+			if (err != nil) != tt.wantErr || token != tt.wantToken {
+				t.Errorf("Token() = %q, %v, want %q wantErr %v", token, err, tt.wantToken, tt.wantErr)
+			}
+			if s.RefreshToken != tt.wantRefresh {
+				t.Errorf("RefreshToken = %q, want %q", s.RefreshToken, tt.wantRefresh)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestFileTokenSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	s := FileTokenSource(path)
+	if _, err := s.Token(); err == nil {
+		t.Error("missing file: err = nil, want an error")
+	}
+	// 每次都重新读取文件
+	for _, content := range []string{" a\n", "b"} {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := s.Token(); err != nil || got != strings.TrimSpace(content) {
+			t.Errorf("Token() = %q, %v, want %q", got, err, strings.TrimSpace(content))
+		}
+	}
+}
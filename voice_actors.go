@@ -0,0 +1,265 @@
+/**
+ * @file 	voice_actors.go
+ * @brief 	统计用户收藏的动画中的声优和角色
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+)
+
+/*
+VoiceActorOptions
+
+  - @brief 统计声优的选项。
+
+    【CollectionTypes】：统计的收藏类型，为nil时为看过和在看。
+
+    【Relations】：只统计这些角色关系，如"主角"，为空时统计全部角色。
+
+    【Concurrency】：最大并发数，小于等于0时为4。请求仍然会经过RateLimit限速。
+
+    【Client】：http.Client对象，为nil时使用http.DefaultClient。
+
+    【Progress】：获取条目角色时的进度回调，done为已完成的条目数，total为条目总数。
+*/
+type VoiceActorOptions struct {
+	CollectionTypes []string
+	Relations       []string
+	Concurrency     int
+	Client          *http.Client
+	Progress        func(done, total int)
+}
+
+/*
+ * @brief 统计结果中的条目
+ */
+type SubjectRef struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	NameCN string `json:"name_cn"`
+}
+
+/*
+ * @brief 统计结果中的角色
+ */
+type CharacterRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+/*
+VoiceActorStat
+
+  - @brief 一个声优的统计。
+
+    【Count】：出演的条目数。
+
+    【Subjects】：出演的条目，按条目ID排序。
+
+    【Characters】：配音的角色，按角色ID排序。
+*/
+type VoiceActorStat struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Count      int            `json:"count"`
+	Subjects   []SubjectRef   `json:"subjects"`
+	Characters []CharacterRef `json:"characters"`
+}
+
+/*
+CharacterStat
+
+  - @brief 一个角色的统计。
+
+    【Count】：出现的条目数。
+
+    【Subjects】：出现的条目，按条目ID排序。
+
+    【Actors】：声优。
+*/
+type CharacterStat struct {
+	ID       int          `json:"id"`
+	Name     string       `json:"name"`
+	Count    int          `json:"count"`
+	Subjects []SubjectRef `json:"subjects"`
+	Actors   []string     `json:"actors"`
+}
+
+/*
+VoiceActorReport
+
+  - @brief 声优和角色的统计结果。
+
+    【Subjects】：统计的条目数。
+
+    【Actors】：声优，按出演的条目数从多到少排序。
+
+    【Characters】：角色，按出现的条目数从多到少排序。
+
+    【Errors】：获取角色失败的条目和错误。
+*/
+type VoiceActorReport struct {
+	UserName   string           `json:"username"`
+	Subjects   int              `json:"subjects"`
+	Actors     []VoiceActorStat `json:"actors"`
+	Characters []CharacterStat  `json:"characters"`
+	Errors     map[int]error    `json:"-"`
+}
+
+/*
+GetVoiceActorStats
+
+  - @brief 统计用户看过、在看的动画中的声优和角色。获取用户的全部动画收藏，再并发获取每个条目的角色和声优。
+
+    API：/v0/users/{username}/collections、/v0/subjects/{subject_id}/characters
+
+  - @param
+
+    【ctx】：context。
+
+    【userName】：用户名。
+
+    【opts】：选项，可以为nil。
+
+  - @return 返回一个*VoiceActorReport和一个err。
+
+  - @retval 只有获取收藏失败时返回err，单个条目的错误记录在VoiceActorReport.Errors中。
+*/
+func GetVoiceActorStats(ctx context.Context, userName string, opts *VoiceActorOptions) (*VoiceActorReport, error) {
+	if opts == nil {
+		opts = &VoiceActorOptions{}
+	}
+	collectionTypes := opts.CollectionTypes
+	if collectionTypes == nil {
+		collectionTypes = []string{"看过", "在看"}
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	relations := make(map[string]bool, len(opts.Relations))
+	for _, r := range opts.Relations {
+		relations[r] = true
+	}
+
+	var subjectIDs []int
+	subjects := make(map[int]SubjectRef)
+	for _, typeName := range collectionTypes {
+		if CollectionTypeID(typeName) == 0 {
+			errMsg := errors.New("GetVoiceActorStats：不匹配的收藏类型")
+			return nil, errMsg
+		}
+		collections, err := GetAllCollectionsByUserName(userName, "动漫", typeName, client)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range collections {
+			if _, ok := subjects[c.SubjectID]; !ok {
+				subjectIDs = append(subjectIDs, c.SubjectID)
+			}
+			subjects[c.SubjectID] = SubjectRef{ID: c.SubjectID, Name: c.Subject.Name, NameCN: c.Subject.NameCN}
+		}
+	}
+
+	report := &VoiceActorReport{
+		UserName: userName,
+		Subjects: len(subjectIDs),
+		Errors:   make(map[int]error),
+	}
+	actors := make(map[int]*VoiceActorStat)
+	actorSubjects := make(map[int]map[int]bool)
+	actorCharacters := make(map[int]map[int]bool)
+	characters := make(map[int]*CharacterStat)
+	characterSubjects := make(map[int]map[int]bool)
+	characterActors := make(map[int]map[string]bool)
+
+	bulk := &BulkOptions{Concurrency: opts.Concurrency, Client: client, Progress: opts.Progress}
+	results := bulkFetch(ctx, subjectIDs, bulk, SearchSubjectsCharactersById)
+	for _, subjectID := range subjectIDs {
+		result := results[subjectID]
+		if result.Err != nil {
+			report.Errors[subjectID] = result.Err
+			continue
+		}
+		var related []RelatedCharacter
+		if err := json.Unmarshal(result.Data, &related); err != nil {
+			report.Errors[subjectID] = errors.New("GetVoiceActorStats：解析条目角色失败")
+			continue
+		}
+		subject := subjects[subjectID]
+
+		for _, c := range related {
+			if len(relations) != 0 && !relations[c.Relation] {
+				continue
+			}
+			cs := characters[c.ID]
+			if cs == nil {
+				cs = &CharacterStat{ID: c.ID, Name: c.Name}
+				characters[c.ID] = cs
+				characterSubjects[c.ID] = make(map[int]bool)
+				characterActors[c.ID] = make(map[string]bool)
+			}
+			if !characterSubjects[c.ID][subjectID] {
+				characterSubjects[c.ID][subjectID] = true
+				cs.Count++
+				cs.Subjects = append(cs.Subjects, subject)
+			}
+
+			for _, a := range c.Actors {
+				if !characterActors[c.ID][a.Name] {
+					characterActors[c.ID][a.Name] = true
+					cs.Actors = append(cs.Actors, a.Name)
+				}
+				as := actors[a.ID]
+				if as == nil {
+					as = &VoiceActorStat{ID: a.ID, Name: a.Name}
+					actors[a.ID] = as
+					actorSubjects[a.ID] = make(map[int]bool)
+					actorCharacters[a.ID] = make(map[int]bool)
+				}
+				if !actorSubjects[a.ID][subjectID] {
+					actorSubjects[a.ID][subjectID] = true
+					as.Count++
+					as.Subjects = append(as.Subjects, subject)
+				}
+				if !actorCharacters[a.ID][c.ID] {
+					actorCharacters[a.ID][c.ID] = true
+					as.Characters = append(as.Characters, CharacterRef{ID: c.ID, Name: c.Name})
+				}
+			}
+		}
+	}
+
+	for _, as := range actors {
+		sort.Slice(as.Subjects, func(i, j int) bool { return as.Subjects[i].ID < as.Subjects[j].ID })
+		sort.Slice(as.Characters, func(i, j int) bool { return as.Characters[i].ID < as.Characters[j].ID })
+		report.Actors = append(report.Actors, *as)
+	}
+	sort.Slice(report.Actors, func(i, j int) bool {
+		if report.Actors[i].Count != report.Actors[j].Count {
+			return report.Actors[i].Count > report.Actors[j].Count
+		}
+		return report.Actors[i].ID < report.Actors[j].ID
+	})
+
+	for _, cs := range characters {
+		sort.Slice(cs.Subjects, func(i, j int) bool { return cs.Subjects[i].ID < cs.Subjects[j].ID })
+		report.Characters = append(report.Characters, *cs)
+	}
+	sort.Slice(report.Characters, func(i, j int) bool {
+		if report.Characters[i].Count != report.Characters[j].Count {
+			return report.Characters[i].Count > report.Characters[j].Count
+		}
+		return report.Characters[i].ID < report.Characters[j].ID
+	})
+	return report, nil
+}
@@ -0,0 +1,108 @@
+package lite_bangumi_api
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+/*
+ * @brief 测试用的收藏：看过1、2，在看2、3，条目3获取角色失败
+ */
+func voiceActorAPI(t *testing.T) *mockAPI {
+	characters := map[string]string{
+		"/v0/subjects/1/characters": `[
+			{"id":100,"name":"C100","relation":"主角","actors":[{"id":1,"name":"A1"},{"id":2,"name":"A2"}]},
+			{"id":101,"name":"C101","relation":"配角","actors":[{"id":1,"name":"A1"}]}]`,
+		"/v0/subjects/2/characters": `[
+			{"id":100,"name":"C100","relation":"主角","actors":[{"id":1,"name":"A1"}]},
+			{"id":102,"name":"C102","relation":"主角","actors":[{"id":3,"name":"A3"}]}]`,
+	}
+	return newMockAPI(t, func(req *http.Request) (int, string) {
+		if req.URL.Path == "/v0/users/sai/collections" {
+			if req.URL.Query().Get("type") == "2" {
+				return http.StatusOK, `{"total":2,"data":[{"subject_id":1,"subject":{"name":"S1"}},{"subject_id":2,"subject":{"name":"S2"}}]}`
+			}
+			return http.StatusOK, `{"total":2,"data":[{"subject_id":2,"subject":{"name":"S2"}},{"subject_id":3,"subject":{"name":"S3"}}]}`
+		}
+		if body, ok := characters[req.URL.Path]; ok {
+			return http.StatusOK, body
+		}
+		return http.StatusNotFound, `{}`
+	})
+}
+
+func TestGetVoiceActorStats(t *testing.T) {
+	tests := []struct {
+		name           string
+		relations      []string
+		wantActors     [][3]int
+		wantCharacters [][2]int
+	}{
+		// 声优：ID、条目数、角色数；角色：ID、条目数
+		{"all characters", nil, [][3]int{{1, 2, 2}, {2, 1, 1}, {3, 1, 1}}, [][2]int{{100, 2}, {101, 1}, {102, 1}}},
+		{"main characters", []string{"主角"}, [][3]int{{1, 2, 1}, {2, 1, 1}, {3, 1, 1}}, [][2]int{{100, 2}, {102, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := voiceActorAPI(t)
+			var mu sync.Mutex
+			var done, total int
+			report, err := GetVoiceActorStats(context.Background(), "sai", &VoiceActorOptions{
+				Relations: tt.relations,
+				Progress: func(d, n int) {
+					mu.Lock()
+					done, total = d, n
+					mu.Unlock()
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actors [][3]int
+			for _, a := range report.Actors {
+				actors = append(actors, [3]int{a.ID, a.Count, len(a.Characters)})
+			}
+			if !reflect.DeepEqual(actors, tt.wantActors) {
+				t.Errorf("actors = %v, want %v", actors, tt.wantActors)
+			}
+			var characters [][2]int
+			for _, c := range report.Characters {
+				characters = append(characters, [2]int{c.ID, c.Count})
+			}
+			if !reflect.DeepEqual(characters, tt.wantCharacters) {
+				t.Errorf("characters = %v, want %v", characters, tt.wantCharacters)
+			}
+			if report.Subjects != 3 || len(report.Errors) != 1 || report.Errors[3] == nil {
+				t.Errorf("subjects %d errors %v, want 3 subjects and an error for 3", report.Subjects, report.Errors)
+			}
+			if done != 3 || total != 3 {
+				t.Errorf("progress = %d/%d, want 3/3", done, total)
+			}
+			if n := api.count("GET /v0/subjects/2/characters"); n != 1 {
+				t.Errorf("subject in two collection types requested %d times, want 1", n)
+			}
+		})
+	}
+}
+
+func TestGetVoiceActorStatsDetails(t *testing.T) {
+	voiceActorAPI(t)
+	report, err := GetVoiceActorStats(context.Background(), "sai", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a1 := report.Actors[0]
+	if want := []SubjectRef{{ID: 1, Name: "S1"}, {ID: 2, Name: "S2"}}; !reflect.DeepEqual(a1.Subjects, want) {
+		t.Errorf("subjects of A1 = %+v, want %+v", a1.Subjects, want)
+	}
+	if want := []string{"A1", "A2"}; !reflect.DeepEqual(report.Characters[0].Actors, want) {
+		t.Errorf("actors of C100 = %v, want %v", report.Characters[0].Actors, want)
+	}
+
+	if _, err = GetVoiceActorStats(context.Background(), "sai", &VoiceActorOptions{CollectionTypes: []string{"看完"}}); err == nil {
+		t.Error("unknown collection type: err = nil, want an error")
+	}
+}
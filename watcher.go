@@ -0,0 +1,594 @@
+/**
+ * @file 	watcher.go
+ * @brief 	监视用户收藏的变化，并通过webhook发送通知
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+/*
+ * @brief 收藏变化的类型
+ */
+type CollectionEventType string
+
+const (
+	CollectionAdded           CollectionEventType = "added"            // 新增收藏
+	CollectionStateChanged    CollectionEventType = "state_changed"    // 收藏类型变化，如在看->看过
+	CollectionRatingChanged   CollectionEventType = "rating_changed"   // 评分变化
+	CollectionProgressChanged CollectionEventType = "progress_changed" // 章节或卷进度变化
+	CollectionRemoved         CollectionEventType = "removed"          // 删除收藏
+)
+
+/*
+ * @brief 默认值
+ */
+const (
+	defaultWatcherInterval   = 10 * time.Minute
+	defaultWebhookRetries    = 3
+	defaultWebhookRetryDelay = time.Second
+)
+
+/*
+CollectionEvent
+
+  - @brief 一次收藏变化。同一个收藏的多个字段同时变化时，每种变化各产生一个事件。
+
+    【ID】：事件ID，同一个变化的ID相同，可以用于去重。
+
+    【Before】【After】：变化前后的收藏，新增时Before为nil，删除时After为nil。
+
+    【OccurredAt】：变化时间，为收藏的更新时间；删除时为发现的时间。
+*/
+type CollectionEvent struct {
+	ID         string                 `json:"id"`
+	Type       CollectionEventType    `json:"type"`
+	UserName   string                 `json:"username"`
+	SubjectID  int                    `json:"subject_id"`
+	Before     *UserSubjectCollection `json:"before,omitempty"`
+	After      *UserSubjectCollection `json:"after,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+/*
+WebhookTarget
+
+  - @brief webhook地址。
+
+    【URL】：接收事件的地址，每个事件以JSON POST一次。
+
+    【Secret】：签名密钥。不为空时请求头X-Bangumi-Signature为"sha256="加上请求体的HMAC-SHA256（十六进制）。
+*/
+type WebhookTarget struct {
+	URL    string
+	Secret string
+}
+
+/*
+WatcherOptions
+
+  - @brief CollectionWatcher的选项。
+
+    【UserName】：用户名。
+
+    【SubjectTypes】：监视的条目类型名，为nil时为全部条目类型。
+
+    【Interval】：检查间隔，小于等于0时为10分钟。
+
+    【StatePath】：保存收藏快照和尚未送达的webhook的文件路径，为空时不保存，重启后第一次检查只记录快照。
+
+    【Webhooks】：webhook地址。
+
+    【MaxRetries】：发送失败时的最大重试次数，小于0时为0，等于0时为3。只有连接失败、429和5xx会重试，重试后仍然失败的webhook留在队列中，下次检查时再发送。
+
+    【RetryDelay】：第一次重试前的等待时间，之后每次加倍，小于等于0时为1秒。
+
+    【OnEvent】：事件回调，可以为nil。
+
+    【OnError】：检查或发送失败时的回调，可以为nil。
+
+    【Client】：访问API的http.Client对象，为nil时使用http.DefaultClient。
+
+    【WebhookClient】：发送webhook的http.Client对象，为nil时使用超时为10秒的http.Client。webhook请求不会携带token，也不会经过拦截器。
+*/
+type WatcherOptions struct {
+	UserName      string
+	SubjectTypes  []string
+	Interval      time.Duration
+	StatePath     string
+	Webhooks      []WebhookTarget
+	MaxRetries    int
+	RetryDelay    time.Duration
+	OnEvent       func(CollectionEvent)
+	OnError       func(error)
+	Client        *http.Client
+	WebhookClient *http.Client
+}
+
+/*
+ * @brief 一次尚未送达的webhook
+ */
+type watcherDelivery struct {
+	URL   string          `json:"url"`
+	Event CollectionEvent `json:"event"`
+}
+
+/*
+ * @brief 保存到文件的状态，快照与CollectionExport的格式相同
+ */
+type watcherState struct {
+	CollectionExport
+	Pending []watcherDelivery `json:"pending,omitempty"`
+}
+
+/*
+CollectionWatcher
+
+  - @brief 定时获取用户收藏的快照，与上一次的快照比较，发现新增、收藏类型变化、评分变化、进度变化和删除，并发送到webhook。
+    新的快照和待发送的webhook一起保存，webhook送达后才从队列中移除，因此取消或进程退出时不会丢失事件。不能并发调用。
+*/
+type CollectionWatcher struct {
+	opts     WatcherOptions
+	snapshot []UserSubjectCollection
+	current  map[int]UserSubjectCollection
+	loaded   bool
+	pending  []watcherDelivery
+	saved    time.Time
+}
+
+/*
+NewCollectionWatcher
+
+  - @brief 创建CollectionWatcher，设置了StatePath时读取上一次的快照和尚未送达的webhook。
+
+  - @param
+
+    【opts】：选项。
+
+  - @return 返回一个*CollectionWatcher和一个err。
+
+  - @retval *CollectionWatcher是CollectionWatcher，err表示错误。如果err为nil，则没有错误。
+*/
+func NewCollectionWatcher(opts WatcherOptions) (*CollectionWatcher, error) {
+	if len(opts.UserName) == 0 {
+		errMsg := errors.New("NewCollectionWatcher：缺少用户名")
+		return nil, errMsg
+	}
+	if opts.SubjectTypes == nil {
+		opts.SubjectTypes = SubjectTypeNames
+	}
+	for _, name := range opts.SubjectTypes {
+		if SubjectTypeID(name) == 0 {
+			errMsg := errors.New("NewCollectionWatcher：不匹配的条目类型")
+			return nil, errMsg
+		}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatcherInterval
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultWebhookRetries
+	} else if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultWebhookRetryDelay
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.WebhookClient == nil {
+		opts.WebhookClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	w := &CollectionWatcher{opts: opts}
+	if len(opts.StatePath) != 0 {
+		var state watcherState
+		found, err := readJSONFile(opts.StatePath, &state)
+		if err != nil {
+			errMsg := errors.New("NewCollectionWatcher：读取快照文件失败")
+			return nil, errMsg
+		}
+		if found {
+			w.snapshot = state.Collections
+			w.current = collectionMap(state.Collections)
+			w.loaded = true
+			w.saved = state.ExportedAt
+			w.pending = state.Pending
+		}
+	}
+	return w, nil
+}
+
+func collectionMap(collections []UserSubjectCollection) map[int]UserSubjectCollection {
+	m := make(map[int]UserSubjectCollection, len(collections))
+	for _, c := range collections {
+		m[c.SubjectID] = c
+	}
+	return m
+}
+
+/*
+Run
+
+  - @brief 立即检查一次，之后每隔Interval检查一次，直到ctx被取消。发现的事件会调用OnEvent，并依次发送到全部webhook。
+    上一次没有送达的webhook会先发送。
+
+  - @param
+
+    【ctx】：context。
+
+  - @return 返回ctx.Err()。
+*/
+func (w *CollectionWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := w.Poll(ctx)
+		if err != nil {
+			w.reportError(err)
+		}
+		if w.opts.OnEvent != nil {
+			for _, event := range events {
+				w.opts.OnEvent(event)
+			}
+		}
+		if err = w.Flush(ctx); err != nil && ctx.Err() == nil {
+			w.reportError(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (w *CollectionWatcher) reportError(err error) {
+	if w.opts.OnError != nil {
+		w.opts.OnError(err)
+	}
+}
+
+/*
+Poll
+
+  - @brief 获取一次快照并与上一次的快照比较。新的快照和发现的事件对应的webhook一起保存，webhook由Flush发送（Run会自动调用）。
+    没有上一次的快照时只记录快照，不返回事件。
+
+    API：/v0/users/{username}/collections
+
+  - @param
+
+    【ctx】：context。
+
+  - @return 返回按变化时间排序的[]CollectionEvent和一个err。
+*/
+func (w *CollectionWatcher) Poll(ctx context.Context) ([]CollectionEvent, error) {
+	var collections []UserSubjectCollection
+	for _, subjectTypeName := range w.opts.SubjectTypes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// 收藏类型为空时获取全部收藏类型
+		page, err := GetAllCollectionsByUserName(w.opts.UserName, subjectTypeName, "", w.opts.Client)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, page...)
+	}
+	now := time.Now()
+	current := collectionMap(collections)
+
+	var events []CollectionEvent
+	if w.loaded {
+		events = DiffCollections(w.opts.UserName, w.current, current, now)
+	}
+	for _, event := range events {
+		for _, target := range w.opts.Webhooks {
+			w.pending = append(w.pending, watcherDelivery{URL: target.URL, Event: event})
+		}
+	}
+	w.snapshot = collections
+	w.current = current
+	w.loaded = true
+	w.saved = now
+
+	if err := w.saveState(); err != nil {
+		return events, err
+	}
+	return events, nil
+}
+
+/*
+Flush
+
+  - @brief 发送队列中尚未送达的webhook。送达或不可重试的失败（如4xx）后从队列中移除，重试后仍然失败的留在队列中，
+    之后发送到同一个地址的webhook也会留在队列中，以保持顺序。已经不在Webhooks中的地址会被移除。
+
+  - @param
+
+    【ctx】：context，取消后停止发送，剩余的webhook留在队列中。
+
+  - @return 返回最后一个错误。
+*/
+func (w *CollectionWatcher) Flush(ctx context.Context) error {
+	targets := make(map[string]WebhookTarget, len(w.opts.Webhooks))
+	for _, target := range w.opts.Webhooks {
+		targets[target.URL] = target
+	}
+
+	var lastErr error
+	blocked := make(map[string]bool)
+	for i := 0; i < len(w.pending); {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		delivery := w.pending[i]
+		target, ok := targets[delivery.URL]
+		if blocked[delivery.URL] {
+			i++
+			continue
+		}
+		if ok {
+			retry, err := w.deliver(ctx, target, delivery.Event)
+			if err != nil {
+				lastErr = err
+				if retry || ctx.Err() != nil {
+					blocked[delivery.URL] = true
+					i++
+					continue
+				}
+			}
+		}
+		w.pending = append(w.pending[:i], w.pending[i+1:]...)
+		if err := w.saveState(); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+/*
+ * @brief 保存快照和尚未送达的webhook
+ */
+func (w *CollectionWatcher) saveState() error {
+	if len(w.opts.StatePath) == 0 || !w.loaded {
+		return nil
+	}
+	state := watcherState{
+		CollectionExport: CollectionExport{UserName: w.opts.UserName, ExportedAt: w.saved, Collections: w.snapshot},
+		Pending:          w.pending,
+	}
+	return writeJSONFile(w.opts.StatePath, state)
+}
+
+/*
+DiffCollections
+
+  - @brief 比较两个收藏快照。
+
+  - @param
+
+    【userName】：用户名，写入事件。
+
+    【before】【after】：以条目ID为键的收藏。
+
+    【now】：删除事件的时间。
+
+  - @return 返回按变化时间排序的[]CollectionEvent。
+*/
+func DiffCollections(userName string, before, after map[int]UserSubjectCollection, now time.Time) []CollectionEvent {
+	var events []CollectionEvent
+	add := func(eventType CollectionEventType, subjectID int, b, a *UserSubjectCollection, at time.Time) {
+		events = append(events, CollectionEvent{
+			ID:         fmt.Sprintf("%s-%d-%s-%d", userName, subjectID, eventType, at.Unix()),
+			Type:       eventType,
+			UserName:   userName,
+			SubjectID:  subjectID,
+			Before:     b,
+			After:      a,
+			OccurredAt: at,
+		})
+	}
+
+	for id, a := range after {
+		a := a
+		b, ok := before[id]
+		if !ok {
+			add(CollectionAdded, id, nil, &a, a.UpdatedAt)
+			continue
+		}
+		if b.Type != a.Type {
+			add(CollectionStateChanged, id, &b, &a, a.UpdatedAt)
+		}
+		if b.Rate != a.Rate {
+			add(CollectionRatingChanged, id, &b, &a, a.UpdatedAt)
+		}
+		if b.EpStatus != a.EpStatus || b.VolStatus != a.VolStatus {
+			add(CollectionProgressChanged, id, &b, &a, a.UpdatedAt)
+		}
+	}
+	for id, b := range before {
+		b := b
+		if _, ok := after[id]; !ok {
+			add(CollectionRemoved, id, &b, nil, now)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].OccurredAt.Equal(events[j].OccurredAt) {
+			return events[i].OccurredAt.Before(events[j].OccurredAt)
+		}
+		if events[i].SubjectID != events[j].SubjectID {
+			return events[i].SubjectID < events[j].SubjectID
+		}
+		return events[i].Type < events[j].Type
+	})
+	return events
+}
+
+/*
+SignWebhookPayload
+
+  - @brief 计算webhook请求体的签名，即X-Bangumi-Signature请求头的值。
+
+  - @param
+
+    【secret】：签名密钥。
+
+    【body】：请求体。
+
+  - @return 返回"sha256="加上HMAC-SHA256的十六进制表示。
+*/
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+/*
+VerifyWebhookSignature
+
+  - @brief 接收方验证webhook请求的签名。
+
+  - @param
+
+    【secret】：签名密钥。
+
+    【body】：请求体。
+
+    【signature】：X-Bangumi-Signature请求头的值。
+
+  - @return 签名正确时返回true。
+*/
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
+
+/*
+Deliver
+
+  - @brief 将一个事件发送到webhook（不经过队列），失败时按RetryDelay加倍等待后重试。
+    请求头包括X-Bangumi-Event（事件类型）、X-Bangumi-Delivery（事件ID）和X-Bangumi-Signature（设置了Secret时）。
+
+  - @param
+
+    【ctx】：context。
+
+    【target】：webhook地址。
+
+    【event】：事件。
+
+  - @return 返回一个err。如果err为nil，则没有错误。
+*/
+func (w *CollectionWatcher) Deliver(ctx context.Context, target WebhookTarget, event CollectionEvent) error {
+	_, err := w.deliver(ctx, target, event)
+	return err
+}
+
+/*
+deliver
+
+  - @brief 与Deliver相同，同时返回失败后是否可以稍后重试
+
+  - @param
+
+    【ctx】：context
+
+    【target】：webhook地址
+
+    【event】：事件
+
+  - @return 返回是否可以重试和一个err。
+*/
+func (w *CollectionWatcher) deliver(ctx context.Context, target WebhookTarget, event CollectionEvent) (bool, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return false, err
+	}
+
+	delay := w.opts.RetryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := w.postWebhook(ctx, target, event, body)
+		if err == nil {
+			return false, nil
+		}
+		if !retry || attempt >= w.opts.MaxRetries {
+			return retry, err
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return true, ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+/*
+postWebhook
+
+  - @brief 发送一次webhook请求
+
+  - @param
+
+    【ctx】：context
+
+    【target】：webhook地址
+
+    【event】：事件
+
+    【body】：请求体
+
+  - @return 返回是否可以重试和一个err。
+*/
+func (w *CollectionWatcher) postWebhook(ctx context.Context, target WebhookTarget, event CollectionEvent, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", target.URL, bytes.NewReader(body))
+	if err != nil {
+		errMsg := errors.New("Deliver：不正确的webhook地址")
+		return false, errMsg
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Bangumi-Event", string(event.Type))
+	req.Header.Set("X-Bangumi-Delivery", event.ID)
+	if len(target.Secret) != 0 {
+		req.Header.Set("X-Bangumi-Signature", SignWebhookPayload(target.Secret, body))
+	}
+	if len(UserAgent) != 0 {
+		req.Header.Set("User-Agent", UserAgent)
+	}
+
+	resp, err := w.opts.WebhookClient.Do(req)
+	if err != nil {
+		errMsg := errors.New("Deliver：连接失败或超时")
+		return ctx.Err() == nil, errMsg
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	errMsg := errors.New("Deliver：错误的返回码:" + strconv.Itoa(resp.StatusCode))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, errMsg
+}
@@ -0,0 +1,225 @@
+package lite_bangumi_api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiffCollections(t *testing.T) {
+	t1 := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	now := t1.Add(24 * time.Hour)
+	before := map[int]UserSubjectCollection{
+		1: {SubjectID: 1, Type: 3, Rate: 0, EpStatus: 5, UpdatedAt: t1},
+		2: {SubjectID: 2, Type: 3, VolStatus: 1, UpdatedAt: t1},
+		3: {SubjectID: 3, Type: 2, Rate: 8, UpdatedAt: t1},
+		4: {SubjectID: 4, Type: 1, UpdatedAt: t1},
+	}
+	after := map[int]UserSubjectCollection{
+		1: {SubjectID: 1, Type: 2, Rate: 9, EpStatus: 5, UpdatedAt: t2},
+		2: {SubjectID: 2, Type: 3, VolStatus: 2, UpdatedAt: t1},
+		3: {SubjectID: 3, Type: 2, Rate: 8, UpdatedAt: t1},
+		5: {SubjectID: 5, Type: 1, UpdatedAt: t2},
+	}
+
+	events := DiffCollections("sai", before, after, now)
+	var got []string
+	for _, e := range events {
+		got = append(got, e.ID)
+	}
+	want := []string{
+		"sai-2-progress_changed-" + strconv.FormatInt(t1.Unix(), 10),
+		"sai-1-rating_changed-" + strconv.FormatInt(t2.Unix(), 10),
+		"sai-1-state_changed-" + strconv.FormatInt(t2.Unix(), 10),
+		"sai-5-added-" + strconv.FormatInt(t2.Unix(), 10),
+		"sai-4-removed-" + strconv.FormatInt(now.Unix(), 10),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+
+	for _, e := range events {
+		switch e.Type {
+		case CollectionAdded:
+			if e.Before != nil || e.After == nil || e.After.SubjectID != 5 {
+				t.Errorf("added event = %+v", e)
+			}
+		case CollectionRemoved:
+			if e.Before == nil || e.After != nil || !e.OccurredAt.Equal(now) {
+				t.Errorf("removed event = %+v", e)
+			}
+		case CollectionStateChanged:
+			if e.Before.Type != 3 || e.After.Type != 2 {
+				t.Errorf("state event = %+v", e)
+			}
+		}
+	}
+	if events := DiffCollections("sai", after, after, now); len(events) != 0 {
+		t.Errorf("unchanged snapshot = %v, want no events", events)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte("The quick brown fox jumps over the lazy dog")
+	const want = "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got := SignWebhookPayload("key", body); got != want {
+		t.Errorf("SignWebhookPayload = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"valid", "key", body, want, true},
+		{"tampered body", "key", []byte(string(body) + "."), want, false},
+		{"wrong secret", "other", body, want, false},
+		{"missing prefix", "key", body, strings.TrimPrefix(want, "sha256="), false},
+		{"empty signature", "key", body, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyWebhookSignature(tt.secret, tt.body, tt.signature); got != tt.want {
+				t.Errorf("VerifyWebhookSignature = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+/*
+ * @brief 测试用的webhook接收方，按地址返回固定的返回码，并记录收到的事件ID
+ */
+type webhookRecorder struct {
+	mu       sync.Mutex
+	status   map[string]int
+	received map[string][]string
+}
+
+func (r *webhookRecorder) client() *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if sig := req.Header.Get("X-Bangumi-Signature"); sig != "" && !VerifyWebhookSignature("s", body, sig) {
+			return &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+		url := req.URL.String()
+		r.received[url] = append(r.received[url], req.Header.Get("X-Bangumi-Delivery"))
+		status := r.status[url]
+		if status == 0 {
+			status = http.StatusOK
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+}
+
+func TestCollectionWatcherFlush(t *testing.T) {
+	recorder := &webhookRecorder{
+		status:   map[string]int{"https://a.test/hook": http.StatusInternalServerError, "https://c.test/hook": http.StatusBadRequest},
+		received: make(map[string][]string),
+	}
+	path := filepath.Join(t.TempDir(), "watcher.json")
+	opts := WatcherOptions{
+		UserName:      "sai",
+		StatePath:     path,
+		Webhooks:      []WebhookTarget{{URL: "https://a.test/hook"}, {URL: "https://b.test/hook", Secret: "s"}, {URL: "https://c.test/hook"}},
+		MaxRetries:    -1,
+		WebhookClient: recorder.client(),
+	}
+	w, err := NewCollectionWatcher(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.loaded = true
+	for _, id := range []string{"e1", "e2"} {
+		for _, target := range append(opts.Webhooks, WebhookTarget{URL: "https://removed.test/hook"}) {
+			w.pending = append(w.pending, watcherDelivery{URL: target.URL, Event: CollectionEvent{ID: id, Type: CollectionAdded}})
+		}
+	}
+
+	if err = w.Flush(context.Background()); err == nil {
+		t.Error("Flush err = nil, want the 500 error")
+	}
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		// 5xx可以重试，之后的webhook留在队列中以保持顺序
+		{"https://a.test/hook", []string{"e1"}},
+		{"https://b.test/hook", []string{"e1", "e2"}},
+		// 4xx不可重试，直接移除
+		{"https://c.test/hook", []string{"e1", "e2"}},
+		{"https://removed.test/hook", nil},
+	}
+	for _, tt := range tests {
+		if got := recorder.received[tt.url]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s received %v, want %v", tt.url, got, tt.want)
+		}
+	}
+
+	// 留在队列中的webhook已保存，重启后继续发送
+	w, err = NewCollectionWatcher(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pending []string
+	for _, d := range w.pending {
+		pending = append(pending, d.URL+" "+d.Event.ID)
+	}
+	if want := []string{"https://a.test/hook e1", "https://a.test/hook e2"}; !reflect.DeepEqual(pending, want) {
+		t.Errorf("pending after restart = %v, want %v", pending, want)
+	}
+
+	recorder.status["https://a.test/hook"] = http.StatusOK
+	if err = w.Flush(context.Background()); err != nil || len(w.pending) != 0 {
+		t.Errorf("Flush = %v with %d pending, want all delivered", err, len(w.pending))
+	}
+	if got := recorder.received["https://a.test/hook"]; !reflect.DeepEqual(got, []string{"e1", "e1", "e2"}) {
+		t.Errorf("a received %v, want [e1 e1 e2]", got)
+	}
+}
+
+func TestCollectionWatcherPoll(t *testing.T) {
+	var mu sync.Mutex
+	body := `{"total":1,"data":[{"subject_id":1,"subject_type":2,"type":3,"ep_status":1,"updated_at":"2026-10-01T00:00:00Z"}]}`
+	newMockAPI(t, func(req *http.Request) (int, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return http.StatusOK, body
+	})
+	w, err := NewCollectionWatcher(WatcherOptions{
+		UserName:     "sai",
+		SubjectTypes: []string{"动漫"},
+		Webhooks:     []WebhookTarget{{URL: "https://a.test/hook"}, {URL: "https://b.test/hook"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if events, err := w.Poll(context.Background()); err != nil || len(events) != 0 {
+		t.Fatalf("first poll = %v, %v, want only a snapshot", events, err)
+	}
+	mu.Lock()
+	body = `{"total":1,"data":[{"subject_id":1,"subject_type":2,"type":3,"ep_status":2,"updated_at":"2026-10-02T00:00:00Z"}]}`
+	mu.Unlock()
+	events, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != CollectionProgressChanged {
+		t.Fatalf("second poll = %+v, want a progress change", events)
+	}
+	if len(w.pending) != 2 {
+		t.Errorf("pending = %d, want one delivery per webhook", len(w.pending))
+	}
+}
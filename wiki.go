@@ -0,0 +1,331 @@
+/**
+ * @file 	wiki.go
+ * @brief 	Bangumi wiki模板（{{Infobox ...}}）的解析和序列化
+ * @author 	AsakuraMori
+ * @version 0.3.0
+ * @date 	2026-10-18
+ */
+
+package lite_bangumi_api
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*
+Wiki
+
+  - @brief 解析后的wiki模板。
+
+    【Type】：模板类型，如animanga/TVAnime。
+
+    【Infobox】：字段，与API返回的infobox结构相同。
+
+    Wiki会保留原文的格式和换行符，未修改的部分在String()中按原文输出。
+*/
+type Wiki struct {
+	Type    string
+	Infobox Infobox
+
+	header   string
+	typeName string
+	fields   []wikiSegment
+	footer   string
+	newline  string
+}
+
+/*
+ * @brief 原文中一个字段对应的文本以及解析时的值
+ */
+type wikiSegment struct {
+	raw  string
+	item InfoboxItem
+}
+
+/*
+wikiSyntaxError
+
+  - @brief 生成带行号的错误
+
+  - @param
+
+    【line】：行号，从1开始
+
+    【msg】：错误信息
+
+  - @return 返回一个err。
+*/
+func wikiSyntaxError(line int, msg string) error {
+	return errors.New("ParseWiki：第" + strconv.Itoa(line) + "行" + msg)
+}
+
+/*
+ParseWiki
+
+  - @brief 解析wiki模板，如编辑历史中的infobox。
+
+    格式如下：
+
+    {{Infobox animanga/TVAnime
+    |中文名= 魔法少女小圆
+    |别名={
+    [魔法少女まどか☆マギカ]
+    [英文|Puella Magi Madoka Magica]
+    }
+    |话数= 12
+    }}
+
+  - @param
+
+    【text】：wiki文本。空白文本会解析为空的Wiki。
+
+  - @return 返回一个*Wiki和一个err。
+
+  - @retval *Wiki是解析结果，err表示错误。如果err为nil，则没有错误。
+*/
+func ParseWiki(text string) (*Wiki, error) {
+	w := &Wiki{newline: "\n"}
+	if strings.Contains(text, "\r\n") {
+		w.newline = "\r\n"
+	}
+	if len(strings.TrimSpace(text)) == 0 {
+		w.header = text
+		return w, nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	// 模板头
+	i := 0
+	for i < len(lines) && len(strings.TrimSpace(lines[i])) == 0 {
+		i++
+	}
+	head := strings.TrimSpace(lines[i])
+	if !strings.HasPrefix(head, "{{Infobox") {
+		return nil, wikiSyntaxError(i+1, "缺少{{Infobox")
+	}
+	w.Type = strings.TrimSpace(strings.TrimPrefix(head, "{{Infobox"))
+	w.typeName = w.Type
+	i++
+	for i < len(lines) && len(strings.TrimSpace(lines[i])) == 0 {
+		i++
+	}
+	w.header = strings.Join(lines[:i], "")
+
+	// 模板尾
+	end := len(lines) - 1
+	for end >= i && len(strings.TrimSpace(lines[end])) == 0 {
+		end--
+	}
+	if end < i {
+		return nil, wikiSyntaxError(end+2, "缺少}}")
+	}
+	last := strings.TrimSpace(lines[end])
+	switch {
+	case last == "}}":
+		w.footer = strings.Join(lines[end:], "")
+	case strings.HasPrefix(last, "|") && strings.HasSuffix(last, "}}"):
+		// }}写在最后一个字段的行尾
+		cut := strings.LastIndex(lines[end], "}}")
+		w.footer = lines[end][cut:] + strings.Join(lines[end+1:], "")
+		lines[end] = lines[end][:cut]
+		end++
+	default:
+		return nil, wikiSyntaxError(end+2, "缺少}}")
+	}
+
+	// 字段
+	for i < end {
+		start := i
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "|") {
+			return nil, wikiSyntaxError(i+1, "缺少|")
+		}
+		key, value, ok := strings.Cut(line[1:], "=")
+		if !ok {
+			return nil, wikiSyntaxError(i+1, "缺少=")
+		}
+		item := InfoboxItem{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)}
+		i++
+
+		if item.Value == "{" {
+			item.Value = ""
+			item.Values = []InfoboxValue{}
+			closed := false
+			for ; i < end; i++ {
+				line = strings.TrimSpace(lines[i])
+				if len(line) == 0 {
+					continue
+				}
+				if line == "}" {
+					closed = true
+					i++
+					break
+				}
+				if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+					return nil, wikiSyntaxError(i+1, "列表项缺少[]")
+				}
+				inner := line[1 : len(line)-1]
+				v := InfoboxValue{V: strings.TrimSpace(inner)}
+				if k, rest, found := strings.Cut(inner, "|"); found {
+					v = InfoboxValue{K: strings.TrimSpace(k), V: strings.TrimSpace(rest)}
+				}
+				item.Values = append(item.Values, v)
+			}
+			if !closed {
+				return nil, wikiSyntaxError(i+1, "列表缺少}")
+			}
+		}
+
+		for i < end && len(strings.TrimSpace(lines[i])) == 0 {
+			i++
+		}
+		w.Infobox = append(w.Infobox, item)
+		w.fields = append(w.fields, wikiSegment{raw: strings.Join(lines[start:i], ""), item: cloneInfoboxItem(item)})
+	}
+	return w, nil
+}
+
+func cloneInfoboxItem(item InfoboxItem) InfoboxItem {
+	if item.Values != nil {
+		item.Values = append([]InfoboxValue{}, item.Values...)
+	}
+	return item
+}
+
+/*
+String
+
+  - @brief 序列化为wiki文本。未修改的部分按原文输出，因此对ParseWiki的结果直接调用String()会得到与原文完全相同的文本；
+    未修改的字段按内容对应到原文，增加、删除或调整顺序不会影响其他字段。修改过的字段按标准格式输出，换行符与原文相同。
+
+  - @return 返回wiki文本。
+*/
+func (w *Wiki) String() string {
+	if len(w.footer) == 0 && len(w.Type) == 0 && len(w.Infobox) == 0 {
+		return w.header
+	}
+	newline := w.newline
+	if len(newline) == 0 {
+		newline = "\n"
+	}
+
+	var b strings.Builder
+	if len(w.footer) != 0 && w.Type == w.typeName {
+		b.WriteString(w.header)
+	} else {
+		b.WriteString(strings.TrimSpace("{{Infobox " + w.Type))
+		b.WriteString(newline)
+	}
+
+	used := make([]bool, len(w.fields))
+	for _, item := range w.Infobox {
+		// 原文中}}写在行尾的字段后面还有字段时需要换行
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString(newline)
+		}
+		if j := w.findSegment(item, used); j >= 0 {
+			used[j] = true
+			b.WriteString(w.fields[j].raw)
+			continue
+		}
+		writeWikiField(&b, item, newline)
+	}
+
+	if len(w.footer) != 0 {
+		b.WriteString(w.footer)
+	} else {
+		b.WriteString("}}")
+	}
+	return b.String()
+}
+
+/*
+findSegment
+
+  - @brief 查找与字段相同且未使用的原文
+
+  - @param
+
+    【item】：字段
+
+    【used】：已经使用的原文
+
+  - @return 返回w.fields中的下标，没有找到时返回-1。
+*/
+func (w *Wiki) findSegment(item InfoboxItem, used []bool) int {
+	for j, segment := range w.fields {
+		if !used[j] && reflect.DeepEqual(segment.item, item) {
+			return j
+		}
+	}
+	return -1
+}
+
+/*
+writeWikiField
+
+  - @brief 按标准格式写出一个字段
+
+  - @param
+
+    【b】：strings.Builder对象
+
+    【item】：字段
+
+    【newline】：换行符
+*/
+func writeWikiField(b *strings.Builder, item InfoboxItem, newline string) {
+	b.WriteString("|" + item.Key + "=")
+	if !item.IsList() {
+		if len(item.Value) != 0 {
+			b.WriteString(" " + item.Value)
+		}
+		b.WriteString(newline)
+		return
+	}
+	b.WriteString("{" + newline)
+	for _, v := range item.Values {
+		if len(v.K) != 0 {
+			b.WriteString("[" + v.K + "|" + v.V + "]" + newline)
+		} else {
+			b.WriteString("[" + v.V + "]" + newline)
+		}
+	}
+	b.WriteString("}" + newline)
+}
+
+/*
+FormatWiki
+
+  - @brief 将infobox按标准格式序列化为wiki文本。
+
+  - @param
+
+    【wikiType】：模板类型，如animanga/TVAnime。
+
+    【infobox】：字段。
+
+  - @return 返回wiki文本。
+*/
+func FormatWiki(wikiType string, infobox Infobox) string {
+	w := &Wiki{Type: wikiType, Infobox: infobox}
+	return w.String()
+}
+
+/*
+ParseInfobox
+
+  - @brief 解析编辑历史中的infobox wiki文本。
+
+  - @return 返回一个*Wiki和一个err。
+*/
+func (c RevisionContent) ParseInfobox() (*Wiki, error) {
+	return ParseWiki(c.Infobox)
+}
@@ -0,0 +1,183 @@
+package lite_bangumi_api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testWiki = "{{Infobox animanga/TVAnime\n" +
+	"|中文名= 魔法少女小圆\n" +
+	"|别名={\n" +
+	"[魔法少女まどか☆マギカ]\n" +
+	"[英文|Puella Magi Madoka Magica]\n" +
+	"}\n" +
+	"|话数=12\n" +
+	"\n" +
+	"|  导演 =  新房昭之  \n" +
+	"}}\n"
+
+func TestParseWikiRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"blank", " \n"},
+		{"standard", testWiki},
+		{"crlf", strings.ReplaceAll(testWiki, "\n", "\r\n")},
+		{"no trailing newline", strings.TrimSuffix(testWiki, "\n")},
+		{"leading and trailing blank lines", "\n\n" + testWiki + "\n\n"},
+		{"closing braces after last field", "{{Infobox animanga/TVAnime\n|中文名= 小圆\n|话数= 12}}\n"},
+		{"closing braces after last field crlf", "{{Infobox\r\n|话数= 12 }}\r\n"},
+		{"no fields", "{{Infobox animanga/TVAnime\n}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseWiki(tt.text)
+			if err != nil {
+				t.Fatalf("ParseWiki: %v", err)
+			}
+			if got := w.String(); got != tt.text {
+				t.Errorf("String() = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestParseWikiFields(t *testing.T) {
+	w, err := ParseWiki(testWiki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Type != "animanga/TVAnime" {
+		t.Errorf("Type = %q", w.Type)
+	}
+	want := Infobox{
+		{Key: "中文名", Value: "魔法少女小圆"},
+		{Key: "别名", Values: []InfoboxValue{{V: "魔法少女まどか☆マギカ"}, {K: "英文", V: "Puella Magi Madoka Magica"}}},
+		{Key: "话数", Value: "12"},
+		{Key: "导演", Value: "新房昭之"},
+	}
+	if !reflect.DeepEqual(w.Infobox, want) {
+		t.Errorf("Infobox = %#v, want %#v", w.Infobox, want)
+	}
+
+	w, err = ParseWiki("{{Infobox\n|中文名= 小圆\n|话数= 12}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Infobox.Get("话数"); got != "12" {
+		t.Errorf("closing braces after last field: 话数 = %q, want 12", got)
+	}
+}
+
+func TestParseWikiErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"missing header", "|a= 1\n}}", "第1行缺少{{Infobox"},
+		{"missing footer", "{{Infobox\n|a= 1\n", "缺少}}"},
+		{"missing pipe", "{{Infobox\na= 1\n}}", "第2行缺少|"},
+		{"missing equals", "{{Infobox\n|a\n}}", "第2行缺少="},
+		{"list item without brackets", "{{Infobox\n|a={\nx\n}\n}}", "第3行列表项缺少[]"},
+		{"unclosed list", "{{Infobox\n|a={\n[x]\n}}", "列表缺少}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWiki(tt.text)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseWiki error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWikiStringMinimalDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		modify func(w *Wiki)
+		want   string
+	}{
+		{
+			name:   "modify one field",
+			text:   testWiki,
+			modify: func(w *Wiki) { w.Infobox[2].Value = "13" },
+			want:   strings.Replace(testWiki, "|话数=12\n\n", "|话数= 13\n", 1),
+		},
+		{
+			name:   "delete a field keeps the following fields",
+			text:   testWiki,
+			modify: func(w *Wiki) { w.Infobox = append(w.Infobox[:1], w.Infobox[2:]...) },
+			want:   strings.Replace(testWiki, "|别名={\n[魔法少女まどか☆マギカ]\n[英文|Puella Magi Madoka Magica]\n}\n", "", 1),
+		},
+		{
+			name: "insert a field keeps the following fields",
+			text: testWiki,
+			modify: func(w *Wiki) {
+				w.Infobox = append(Infobox{{Key: "放送开始", Value: "2011年1月7日"}}, w.Infobox...)
+			},
+			want: strings.Replace(testWiki, "|中文名", "|放送开始= 2011年1月7日\n|中文名", 1),
+		},
+		{
+			name: "swap fields",
+			text: "{{Infobox\n|a=1\n|b = 2\n}}",
+			modify: func(w *Wiki) {
+				w.Infobox[0], w.Infobox[1] = w.Infobox[1], w.Infobox[0]
+			},
+			want: "{{Infobox\n|b = 2\n|a=1\n}}",
+		},
+		{
+			name: "crlf document keeps crlf",
+			text: "{{Infobox\r\n|a=1\r\n}}\r\n",
+			modify: func(w *Wiki) {
+				w.Infobox = append(w.Infobox, InfoboxItem{Key: "b", Values: []InfoboxValue{{K: "k", V: "v"}}})
+			},
+			want: "{{Infobox\r\n|a=1\r\n|b={\r\n[k|v]\r\n}\r\n}}\r\n",
+		},
+		{
+			name: "append after closing braces on the field line",
+			text: "{{Infobox\n|a=1}}\n",
+			modify: func(w *Wiki) {
+				w.Infobox = append(w.Infobox, InfoboxItem{Key: "b", Value: "2"})
+			},
+			want: "{{Infobox\n|a=1\n|b= 2\n}}\n",
+		},
+		{
+			name:   "change type rewrites the header",
+			text:   "{{Infobox animanga/TVAnime\r\n|a=1\r\n}}",
+			modify: func(w *Wiki) { w.Type = "animanga/Movie" },
+			want:   "{{Infobox animanga/Movie\r\n|a=1\r\n}}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseWiki(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(w)
+			if got := w.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWiki(t *testing.T) {
+	got := FormatWiki("animanga/TVAnime", Infobox{
+		{Key: "中文名", Value: "小圆"},
+		{Key: "备注"},
+		{Key: "别名", Values: []InfoboxValue{{V: "A"}, {K: "英文", V: "B"}}},
+	})
+	want := "{{Infobox animanga/TVAnime\n|中文名= 小圆\n|备注=\n|别名={\n[A]\n[英文|B]\n}\n}}"
+	if got != want {
+		t.Errorf("FormatWiki = %q, want %q", got, want)
+	}
+	if w, err := ParseWiki(got); err != nil || w.String() != got {
+		t.Errorf("FormatWiki output does not round-trip: %v", err)
+	}
+}